@@ -0,0 +1,189 @@
+package bitarray
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+// The arithmetic operations treat a BitArray as an unsigned integer, with
+// index 0 as its least significant bit, like Add and Sub. The result has
+// the length of the longer operand and the shorter one is zero-extended.
+
+// IsZero reports whether no bit is set.
+func (b *BitArray) IsZero() bool {
+	for _, u := range b.blocks {
+		if u != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Cmp compares two BitArrays as unsigned integers and returns -1 if x < y,
+// 0 if x == y and +1 if x > y.
+func Cmp(x, y *BitArray) int {
+	return cmpBlocks(x.used(), y.used())
+}
+
+// cmpBlocks compares two unsigned integers stored in blocks.
+func cmpBlocks(xs, ys []uint64) int {
+	for i := maxLength(len(xs), len(ys)) - 1; i >= 0; i-- {
+		u, v := word(xs, i), word(ys, i)
+		if u < v {
+			return -1
+		}
+
+		if u > v {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// Mul returns the product of two BitArrays wrapped around to the length of
+// the longer one, and whether the product overflowed that length.
+func Mul(x, y *BitArray) (*BitArray, bool, error) {
+	bitArray, err := NewBitArray(maxLength(x.length, y.length))
+	if err != nil {
+		return nil, false, err
+	}
+
+	xs, ys := x.used(), y.used()
+	z := bitArray.blocks
+	n := len(z)
+	overflow := false
+	for i, u := range xs {
+		if u == 0 {
+			continue
+		}
+
+		carry := uint64(0)
+		for j, v := range ys {
+			if i+j >= n {
+				if v != 0 || carry != 0 {
+					overflow = true
+				}
+
+				carry = 0
+				continue
+			}
+
+			hi, lo := bits.Mul64(u, v)
+			var c uint64
+			lo, c = bits.Add64(lo, z[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			z[i+j] = lo
+			carry = hi
+		}
+
+		if carry != 0 {
+			if i+len(ys) < n {
+				z[i+len(ys)] = carry
+			} else {
+				overflow = true
+			}
+		}
+	}
+
+	mod := bitArray.length % bitPerBlock
+	if mod != 0 && z[n-1]>>uint64(mod) != 0 {
+		overflow = true
+		bitArray.clearTail()
+	}
+
+	return bitArray, overflow, nil
+}
+
+// DivMod returns the quotient and the remainder of the division of x by y.
+func DivMod(x, y *BitArray) (*BitArray, *BitArray, error) {
+	if y.IsZero() {
+		return nil, nil, errors.New("division by zero")
+	}
+
+	length := maxLength(x.length, y.length)
+	q, err := NewBitArray(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := NewBitArray(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	xs, ys := x.used(), y.used()
+	for len(ys) > 0 && ys[len(ys)-1] == 0 {
+		ys = ys[:len(ys)-1]
+	}
+
+	if len(ys) == 1 {
+		rem := uint64(0)
+		for i := len(xs) - 1; i >= 0; i-- {
+			q.blocks[i], rem = bits.Div64(rem, xs[i], ys[0])
+		}
+
+		if len(r.blocks) > 0 {
+			r.blocks[0] = rem
+		}
+
+		return q, r, nil
+	}
+
+	// The remainder is kept one block wider, since shifting it may
+	// carry out of its length before y is subtracted.
+	rem := make([]uint64, len(ys)+1)
+	for i := len(xs)*bitPerBlock - 1; i >= 0; i-- {
+		carry := xs[i/bitPerBlock] >> uint64(i%bitPerBlock) & 1
+		for j := range rem {
+			rem[j], carry = rem[j]<<1|carry, rem[j]>>(bitPerBlock-1)
+		}
+
+		if cmpBlocks(rem, ys) >= 0 {
+			borrow := uint64(0)
+			for j := range rem {
+				rem[j], borrow = bits.Sub64(rem[j], word(ys, j), borrow)
+			}
+
+			q.blocks[i/bitPerBlock] |= 1 << uint64(i%bitPerBlock)
+		}
+	}
+
+	copy(r.blocks, rem)
+	return q, r, nil
+}
+
+// BigInt returns the value of the BitArray as an unsigned integer.
+func (b *BitArray) BigInt() *big.Int {
+	data := b.Bytes(LSBFirst)
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+
+	return new(big.Int).SetBytes(data)
+}
+
+// FromBigInt is BitArray constructed from an integer reduced modulo 2^length,
+// so that negative integers are stored in two's complement.
+func FromBigInt(x *big.Int, length int) (*BitArray, error) {
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		return nil, err
+	}
+
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(length))
+	data := new(big.Int).Mod(x, modulus).Bytes()
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+
+	for i, v := range data {
+		bitArray.blocks[i/8] |= uint64(v) << uint64(i%8*8)
+	}
+
+	return bitArray, nil
+}
@@ -0,0 +1,147 @@
+package bitarray
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// newRandomBitArray returns a BitArray with random bits, with about half of
+// them being left zero at the top to vary the magnitude.
+func newRandomBitArray(t *testing.T, length int) *BitArray {
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	top := rand.Intn(length + 1)
+	for i := 0; i < top; i++ {
+		if rand.Intn(2) == 0 {
+			if err := bitArray.Set(i); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return bitArray
+}
+
+func TestMul(t *testing.T) {
+	rand.Seed(1)
+	lengths := []int{1, 7, 63, 64, 65, 128, 200}
+	for _, xLength := range lengths {
+		for _, yLength := range lengths {
+			for n := 0; n < 20; n++ {
+				x := newRandomBitArray(t, xLength)
+				y := newRandomBitArray(t, yLength)
+				length := maxLength(xLength, yLength)
+				modulus := new(big.Int).Lsh(big.NewInt(1), uint(length))
+				xb, yb := x.BigInt(), y.BigInt()
+
+				product := new(big.Int).Mul(xb, yb)
+				z, overflow, err := Mul(x, y)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if overflow != (product.Cmp(modulus) >= 0) {
+					t.Errorf("overflow does not match %v %v %v", xb, yb, overflow)
+				}
+
+				if z.length != length || z.BigInt().Cmp(product.Mod(product, modulus)) != 0 {
+					t.Errorf("product does not match %v %v %v", xb, yb, z.BigInt())
+				}
+
+				if y.IsZero() {
+					if _, _, err := DivMod(x, y); err == nil {
+						t.Error("expected error")
+					}
+
+					continue
+				}
+
+				q, r, err := DivMod(x, y)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				qb, rb := new(big.Int).QuoRem(xb, yb, new(big.Int))
+				if q.length != length || r.length != length || q.BigInt().Cmp(qb) != 0 || r.BigInt().Cmp(rb) != 0 {
+					t.Errorf("quotient does not match %v %v %v %v", xb, yb, q.BigInt(), r.BigInt())
+				}
+
+				if Cmp(x, y) != xb.Cmp(yb) {
+					t.Errorf("comparison does not match %v %v", xb, yb)
+				}
+			}
+		}
+	}
+}
+
+func TestFromBigInt(t *testing.T) {
+	for length := 0; length < 200; length++ {
+		bitArray := newRandomBitArray(t, length)
+		converted, err := FromBigInt(bitArray.BigInt(), length)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkBools(t, "big int", converted, bitArray.Bools())
+
+		if bitArray.IsZero() != (bitArray.BigInt().Sign() == 0) {
+			t.Errorf("zero does not match %v", bitArray)
+		}
+	}
+
+	minusOne, err := FromBigInt(big.NewInt(-1), 70)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if minusOne.OnesCount() != 70 {
+		t.Errorf("value does not match %v", minusOne)
+	}
+
+	wrapped, err := FromBigInt(big.NewInt(0x1ff), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if wrapped.String() != "11111111" {
+		t.Errorf("value does not match %v", wrapped)
+	}
+}
+
+func TestSub(t *testing.T) {
+	rand.Seed(1)
+	lengths := []int{0, 1, 7, 63, 64, 65, 128, 200}
+	for _, xLength := range lengths {
+		for _, yLength := range lengths {
+			for n := 0; n < 20; n++ {
+				x := newRandomBitArray(t, xLength)
+				y := newRandomBitArray(t, yLength)
+				length := maxLength(xLength, yLength)
+				modulus := new(big.Int).Lsh(big.NewInt(1), uint(length))
+				for _, borrow := range []bool{false, true} {
+					difference := new(big.Int).Sub(x.BigInt(), y.BigInt())
+					if borrow {
+						difference.Sub(difference, big.NewInt(1))
+					}
+
+					z, b, err := Sub(x, y, borrow)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if b != (difference.Sign() < 0) {
+						t.Errorf("borrow does not match %v %v %v", x.BigInt(), y.BigInt(), b)
+					}
+
+					if z.length != length || z.BigInt().Cmp(difference.Mod(difference, modulus)) != 0 {
+						t.Errorf("difference does not match %v %v %v", x.BigInt(), y.BigInt(), z.BigInt())
+					}
+				}
+			}
+		}
+	}
+}
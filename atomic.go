@@ -0,0 +1,125 @@
+package bitarray
+
+import (
+	"errors"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicBitArray is a BitArray that is safe for concurrent use.
+// Each bit operation is an atomic compare-and-swap on its block,
+// so concurrent operations on bits of the same block do not lose updates.
+type AtomicBitArray struct {
+	// mu is held shared by bit operations, which may run concurrently,
+	// and exclusively by OnesCount and Snapshot to see a consistent state.
+	mu     sync.RWMutex
+	blocks []uint64
+	length int
+}
+
+// NewAtomicBitArray is AtomicBitArray constructed.
+func NewAtomicBitArray(length int) (*AtomicBitArray, error) {
+	if length < 0 {
+		return nil, errors.New("negative length argument")
+	}
+
+	return &AtomicBitArray{
+		blocks: make([]uint64, blockCount(length)),
+		length: length,
+	}, nil
+}
+
+// Length returns number of bits in the AtomicBitArray.
+func (b *AtomicBitArray) Length() int {
+	return b.length
+}
+
+// update atomically applies f to the block holding the specified bit
+// and returns whether the bit was set before.
+func (b *AtomicBitArray) update(index int, f func(u, mask uint64) uint64) (bool, error) {
+	if index < 0 || index >= b.length {
+		return false, errors.New("index out of range")
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	addr := &b.blocks[index/bitPerBlock]
+	mask := uint64(1) << uint64(index%bitPerBlock)
+	for {
+		u := atomic.LoadUint64(addr)
+		if atomic.CompareAndSwapUint64(addr, u, f(u, mask)) {
+			return u&mask != 0, nil
+		}
+	}
+}
+
+func setMask(u, mask uint64) uint64 {
+	return u | mask
+}
+
+func clearMask(u, mask uint64) uint64 {
+	return u &^ mask
+}
+
+// Set sets the specified bit to true.
+func (b *AtomicBitArray) Set(index int) error {
+	_, err := b.update(index, setMask)
+	return err
+}
+
+// Clear sets the specified bit to false.
+func (b *AtomicBitArray) Clear(index int) error {
+	_, err := b.update(index, clearMask)
+	return err
+}
+
+// TestAndSet sets the specified bit to true and returns whether it was already set.
+func (b *AtomicBitArray) TestAndSet(index int) (bool, error) {
+	return b.update(index, setMask)
+}
+
+// TestAndClear sets the specified bit to false and returns whether it was set.
+func (b *AtomicBitArray) TestAndClear(index int) (bool, error) {
+	return b.update(index, clearMask)
+}
+
+// Get gets the specified bit.
+func (b *AtomicBitArray) Get(index int) (bool, error) {
+	if index < 0 || index >= b.length {
+		return false, errors.New("index out of range")
+	}
+
+	u := atomic.LoadUint64(&b.blocks[index/bitPerBlock])
+	return u&(1<<uint64(index%bitPerBlock)) != 0, nil
+}
+
+// OnesCount returns the number of one bits in the AtomicBitArray.
+// The count is taken from a consistent state: bit operations running
+// concurrently happen either entirely before or entirely after it.
+func (b *AtomicBitArray) OnesCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	count := 0
+	for _, u := range b.blocks {
+		count += bits.OnesCount64(u)
+	}
+
+	return count
+}
+
+// Snapshot copies a consistent state of the AtomicBitArray into a new BitArray.
+func (b *AtomicBitArray) Snapshot() (*BitArray, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bitArray, err := NewBitArray(b.length)
+	if err != nil {
+		return nil, err
+	}
+
+	copy(bitArray.blocks, b.blocks)
+	return bitArray, nil
+}
@@ -0,0 +1,117 @@
+package bitarray
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAtomicBitArray(t *testing.T) {
+	const length = 1000
+	const workers = 8
+
+	bitArray, err := NewAtomicBitArray(length)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < length; i += workers {
+				if err := bitArray.Set(i); err != nil {
+					t.Error(err)
+				}
+			}
+		}(w)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		prev := 0
+		for prev < length {
+			count := bitArray.OnesCount()
+			if count < prev {
+				t.Errorf("count decreased %v %v", count, prev)
+			}
+
+			prev = count
+		}
+	}()
+
+	wg.Wait()
+
+	if count := bitArray.OnesCount(); count != length {
+		t.Errorf("count does not match %v", count)
+	}
+
+	winners := make([]int, length)
+	var mu sync.Mutex
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < length; i++ {
+				wasSet, err := bitArray.TestAndClear(i)
+				if err != nil {
+					t.Error(err)
+				}
+
+				if wasSet {
+					mu.Lock()
+					winners[i]++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i, n := range winners {
+		if n != 1 {
+			t.Errorf("bit %v was cleared %v times", i, n)
+		}
+	}
+
+	snapshot, err := bitArray.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot.OnesCount() != 0 {
+		t.Errorf("count does not match %v", snapshot.OnesCount())
+	}
+
+	wasSet, err := bitArray.TestAndSet(5)
+	if err != nil || wasSet {
+		t.Errorf("unexpected result %v %v", wasSet, err)
+	}
+
+	wasSet, err = bitArray.TestAndSet(5)
+	if err != nil || !wasSet {
+		t.Errorf("unexpected result %v %v", wasSet, err)
+	}
+
+	if v, err := bitArray.Get(5); err != nil || !v {
+		t.Errorf("unexpected result %v %v", v, err)
+	}
+
+	if err := bitArray.Clear(5); err != nil {
+		t.Error(err)
+	}
+
+	if v, err := bitArray.Get(5); err != nil || v {
+		t.Errorf("unexpected result %v %v", v, err)
+	}
+
+	if err := bitArray.Set(length); err == nil {
+		t.Error("expected error")
+	}
+
+	if _, err := bitArray.Get(-1); err == nil {
+		t.Error("expected error")
+	}
+}
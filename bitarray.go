@@ -15,6 +15,24 @@ type BitArray struct {
 const bitPerBlock = 64
 const max = math.MaxUint64
 
+// blockCount returns the number of blocks needed to hold length bits.
+func blockCount(length int) int {
+	size := length / bitPerBlock
+	if length%bitPerBlock != 0 {
+		size++
+	}
+
+	return size
+}
+
+// clearTail clears the bits beyond the length in the last block.
+func (b *BitArray) clearTail() {
+	mod := b.length % bitPerBlock
+	if mod != 0 {
+		b.blocks[blockCount(b.length)-1] &= uint64(max) >> uint64(bitPerBlock-mod)
+	}
+}
+
 // NewBitArray is BitArray constructed.
 func NewBitArray(length int) (*BitArray, error) {
 	if length < 0 {
@@ -77,8 +95,11 @@ func (b *BitArray) Clear(index int) error {
 }
 
 // Reset set all bitPerBlock to false.
+// The storage of the BitArray is kept.
 func (b *BitArray) Reset() {
-	b.blocks = make([]uint64, b.length/bitPerBlock+1)
+	for i := range b.blocks {
+		b.blocks[i] = 0
+	}
 }
 
 // Length returns number of bitPerBlock in the BitArray.
@@ -128,37 +149,34 @@ func (b *BitArray) Append(elem *BitArray) (*BitArray, error) {
 }
 
 // Slice the BitArray.
+// Bits before index 0 or beyond the length are filled with zero.
 func (b *BitArray) Slice(start, end int) (*BitArray, error) {
-	size := end - start
-	bitArray, err := NewBitArray(size)
+	bitArray, err := NewBitArray(end - start)
 	if err != nil {
 		return nil, err
 	}
 
-	copySize := size
-	if size > b.length-start {
-		copySize = b.length - start
-	}
-
-	i := 0
-	if start < 0 {
-		i = -start
+	for i := range bitArray.blocks {
+		bitArray.blocks[i] = b.blockAt(start + i*bitPerBlock)
 	}
 
-	for ; i < copySize; i++ {
-		isSet, err := b.Get(i + start)
-		if err != nil {
-			return nil, err
-		}
+	bitArray.clearTail()
+	return bitArray, nil
+}
 
-		if isSet {
-			if err := bitArray.Set(i); err != nil {
-				return nil, err
-			}
-		}
+// blockAt returns the 64 bits starting at the specified index.
+// Bits before index 0 or beyond the length are zero.
+func (b *BitArray) blockAt(index int) uint64 {
+	blocks := b.used()
+	i := index / bitPerBlock
+	mod := index % bitPerBlock
+	if mod < 0 {
+		i--
+		mod += bitPerBlock
 	}
 
-	return bitArray, nil
+	shift := uint64(mod)
+	return word(blocks, i)>>shift | word(blocks, i+1)<<(bitPerBlock-shift)
 }
 
 // Clone the BitArray.
@@ -182,85 +200,27 @@ func (b *BitArray) Not() (*BitArray, error) {
 		return nil, err
 	}
 
-	for i, v := range b.blocks {
-		bitArray.blocks[i] = ^v
-	}
-
-	if len(b.blocks) > 0 && b.length%bitPerBlock != 0 {
-		mask := uint64(max) >> uint64(bitPerBlock-b.length%bitPerBlock)
-		bitArray.blocks[len(b.blocks)-1] &= mask
-	}
-
-	return bitArray, err
+	notWords(bitArray.blocks, b.used())
+	bitArray.clearTail()
+	return bitArray, nil
 }
 
 // And is the logical AND of two BitArrays.
+// The result has the length of the longer one, see PadToLonger.
 func And(x, y *BitArray) (*BitArray, error) {
-	if x.length > y.length {
-		x, y = y, x
-	}
-
-	bitArray, err := NewBitArray(y.length)
-	if err != nil {
-		return nil, err
-	}
-
-	for i, v := range x.blocks {
-		bitArray.blocks[i] = v & y.blocks[i]
-	}
-
-	if len(x.blocks) > 0 && x.length%bitPerBlock != 0 {
-		mask := uint64(max) >> uint64(bitPerBlock-x.length%bitPerBlock)
-		bitArray.blocks[len(x.blocks)-1] &= mask
-	}
-
-	return bitArray, nil
+	return AndWithPolicy(x, y, PadToLonger)
 }
 
 // Or is the logical OR of two BitArrays.
+// The result has the length of the longer one, see PadToLonger.
 func Or(x, y *BitArray) (*BitArray, error) {
-	if x.length > y.length {
-		x, y = y, x
-	}
-
-	bitArray, err := NewBitArray(y.length)
-	if err != nil {
-		return nil, err
-	}
-
-	for i, v := range x.blocks {
-		bitArray.blocks[i] = v | y.blocks[i]
-	}
-
-	if len(x.blocks) > 0 && x.length%bitPerBlock != 0 {
-		mask := uint64(max) >> uint64(bitPerBlock-x.length%bitPerBlock)
-		bitArray.blocks[len(x.blocks)-1] &= mask
-	}
-
-	return bitArray, nil
+	return OrWithPolicy(x, y, PadToLonger)
 }
 
 // Xor is the Exclusive OR of two BitArrays.
+// The result has the length of the longer one, see PadToLonger.
 func Xor(x, y *BitArray) (*BitArray, error) {
-	if x.length > y.length {
-		x, y = y, x
-	}
-
-	bitArray, err := NewBitArray(y.length)
-	if err != nil {
-		return nil, err
-	}
-
-	for i, v := range x.blocks {
-		bitArray.blocks[i] = v ^ y.blocks[i]
-	}
-
-	if len(x.blocks) > 0 && x.length%bitPerBlock != 0 {
-		mask := uint64(max) >> uint64(bitPerBlock-x.length%bitPerBlock)
-		bitArray.blocks[len(x.blocks)-1] &= mask
-	}
-
-	return bitArray, nil
+	return XorWithPolicy(x, y, PadToLonger)
 }
 
 // AndNot clears bitPerBlock specified by argument BitArray.
@@ -290,6 +250,7 @@ func (b *BitArray) AndNot(bitArray *BitArray) (*BitArray, error) {
 }
 
 // LeftShift shifts the BitArray to the left.
+// The length grows by n, see Shift for the other shift modes.
 func (b *BitArray) LeftShift(n int) (*BitArray, error) {
 	if n < 0 {
 		return b.RightShift(-n)
@@ -324,6 +285,7 @@ func (b *BitArray) LeftShift(n int) (*BitArray, error) {
 }
 
 // RightShift shifts the BitArray to the right.
+// The length is kept, see Shift for the other shift modes.
 func (b *BitArray) RightShift(n int) (*BitArray, error) {
 	if n < 0 {
 		return b.LeftShift(-n)
@@ -379,12 +341,7 @@ func (b *BitArray) ReverseBytes() (*BitArray, error) {
 
 // OnesCount returns the number of one bits in the BitArray.
 func (b *BitArray) OnesCount() int {
-	count := 0
-	for i := 0; i < len(b.blocks); i++ {
-		count += bits.OnesCount64(b.blocks[i])
-	}
-
-	return count
+	return popcount(b.used())
 }
 
 // TrailingZeros returns the number of trailing zero bits in the BitArray.
@@ -445,14 +402,9 @@ func Add(x, y *BitArray, carry bool) (*BitArray, bool, error) {
 }
 
 // Sub returns the difference of two BitArrays and borrow.
+// The result has the length of the longer one and the shorter one is zero-extended.
 func Sub(x, y *BitArray, borrow bool) (*BitArray, bool, error) {
-	xLen, yLen := len(x.blocks), len(y.blocks)
-	small := xLen
-	if xLen < yLen {
-		small = yLen
-	}
-
-	bitArray, err := NewBitArray(x.length)
+	bitArray, err := NewBitArray(maxLength(x.length, y.length))
 	if err != nil {
 		return nil, false, err
 	}
@@ -462,27 +414,18 @@ func Sub(x, y *BitArray, borrow bool) (*BitArray, bool, error) {
 		b = 1
 	}
 
-	for i := 0; i < small; i++ {
-		bitArray.blocks[i], b = bits.Sub64(x.blocks[i], y.blocks[i], b)
-	}
-
-	if xLen > yLen {
-		for i := len(y.blocks); i < len(x.blocks); i++ {
-			bitArray.blocks[i], b = bits.Sub64(x.blocks[i], 0, b)
-		}
-	} else {
-		for i := len(y.blocks); i < len(x.blocks); i++ {
-			bitArray.blocks[i], b = bits.Sub64(0, y.blocks[i], b)
-		}
+	xs, ys := x.used(), y.used()
+	for i := range bitArray.blocks {
+		bitArray.blocks[i], b = bits.Sub64(word(xs, i), word(ys, i), b)
 	}
 
 	mod := bitArray.length % bitPerBlock
 	if mod != 0 {
 		mask := ^uint64(0) >> uint64(bitPerBlock-mod)
-		u := bitArray.blocks[len(x.blocks)-1]
+		u := bitArray.blocks[len(bitArray.blocks)-1]
 		if u & ^mask != 0 {
 			b = 1
-			bitArray.blocks[len(x.blocks)-1] = u & mask
+			bitArray.blocks[len(bitArray.blocks)-1] = u & mask
 		}
 	}
 
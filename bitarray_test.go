@@ -1,6 +1,7 @@
 package bitarray
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -678,6 +679,83 @@ func TestBitArray_Slice(t *testing.T) {
 	}
 }
 
+func TestBitArray_SliceBounds(t *testing.T) {
+	for _, length := range []int{0, 1, 63, 64, 65, 200} {
+		bitArray, err := NewBitArray(length)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < bitArray.length; i += 3 {
+			if err := bitArray.Set(i); err != nil {
+				t.Error(err)
+			}
+		}
+
+		for start := -130; start < length+130; start += 7 {
+			for end := start; end < length+200; end += 11 {
+				expected, err := sliceBitByBit(bitArray, start, end)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				bitSlice, err := bitArray.Slice(start, end)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if bitSlice.length != expected.length {
+					t.Fatalf("length does not match %v %v %v", length, start, end)
+				}
+
+				for i := range expected.blocks {
+					if bitSlice.blocks[i] != expected.blocks[i] {
+						t.Errorf("value does not match %v %v %v %v", length, start, end, i)
+					}
+				}
+			}
+		}
+
+		if _, err := bitArray.Slice(1, 0); err == nil {
+			t.Error("expected error")
+		}
+	}
+}
+
+// sliceBitByBit is the reference implementation of Slice using Get and Set.
+func sliceBitByBit(b *BitArray, start, end int) (*BitArray, error) {
+	size := end - start
+	bitArray, err := NewBitArray(size)
+	if err != nil {
+		return nil, err
+	}
+
+	copySize := size
+	if size > b.length-start {
+		copySize = b.length - start
+	}
+
+	i := 0
+	if start < 0 {
+		i = -start
+	}
+
+	for ; i < copySize; i++ {
+		isSet, err := b.Get(i + start)
+		if err != nil {
+			return nil, err
+		}
+
+		if isSet {
+			if err := bitArray.Set(i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return bitArray, nil
+}
+
 func TestBitArray_Clone(t *testing.T) {
 	for length := 0; length < 10000; length++ {
 		bitArray, err := NewBitArray(length)
@@ -767,3 +845,41 @@ func BenchmarkBoolSlice_Set(b *testing.B) {
 		boolSlice[i] = true
 	}
 }
+
+func benchmarkSlice(b *testing.B, start int, slice func(*BitArray, int, int) (*BitArray, error)) {
+	bitArray, err := NewBitArray(1 << 20)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for i := 0; i < bitArray.length; i += 7 {
+		if err := bitArray.Set(i); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := slice(bitArray, start, start+1<<19); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBitArray_Slice(b *testing.B) {
+	for _, start := range []int{0, 1, 32, 63} {
+		b.Run(fmt.Sprintf("start=%v", start), func(b *testing.B) {
+			benchmarkSlice(b, start, (*BitArray).Slice)
+		})
+	}
+}
+
+func BenchmarkBitArray_SliceBitByBit(b *testing.B) {
+	for _, start := range []int{0, 1, 32, 63} {
+		b.Run(fmt.Sprintf("start=%v", start), func(b *testing.B) {
+			benchmarkSlice(b, start, sliceBitByBit)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package bitarray
+
+// Bitmap is the interface implemented by the dense BitArray and the compressed Roaring.
+type Bitmap interface {
+	Set(index int) error
+	Get(index int) (bool, error)
+	Clear(index int) error
+	OnesCount() int
+	Length() int
+	SetBits() func(yield func(int) bool)
+}
+
+var (
+	_ Bitmap = (*BitArray)(nil)
+	_ Bitmap = (*Roaring)(nil)
+)
+
+// toRoaring converts a Bitmap to a Roaring.
+func toRoaring(x Bitmap) (*Roaring, error) {
+	switch v := x.(type) {
+	case *Roaring:
+		return v, nil
+	case *BitArray:
+		return v.Roaring()
+	}
+
+	r, err := NewRoaring(x.Length())
+	if err != nil {
+		return nil, err
+	}
+
+	x.SetBits()(func(index int) bool {
+		err = r.Set(index)
+		return err == nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// bitmapOp applies op to two BitArrays, or otherwise converts both Bitmaps to Roaring and applies roaringOp.
+func bitmapOp(x, y Bitmap, op func(x, y *BitArray) (*BitArray, error), roaringOp func(x, y *Roaring) *Roaring) (Bitmap, error) {
+	if xb, ok := x.(*BitArray); ok {
+		if yb, ok := y.(*BitArray); ok {
+			z, err := op(xb, yb)
+			if err != nil {
+				return nil, err
+			}
+
+			return z, nil
+		}
+	}
+
+	xr, err := toRoaring(x)
+	if err != nil {
+		return nil, err
+	}
+
+	yr, err := toRoaring(y)
+	if err != nil {
+		return nil, err
+	}
+
+	return roaringOp(xr, yr), nil
+}
+
+// AndBitmap is the logical AND of two Bitmaps.
+// The result is a BitArray if both are BitArrays, and a Roaring otherwise.
+func AndBitmap(x, y Bitmap) (Bitmap, error) {
+	return bitmapOp(x, y, And, AndRoaring)
+}
+
+// OrBitmap is the logical OR of two Bitmaps.
+// The result is a BitArray if both are BitArrays, and a Roaring otherwise.
+func OrBitmap(x, y Bitmap) (Bitmap, error) {
+	return bitmapOp(x, y, Or, OrRoaring)
+}
+
+// XorBitmap is the exclusive OR of two Bitmaps.
+// The result is a BitArray if both are BitArrays, and a Roaring otherwise.
+func XorBitmap(x, y Bitmap) (Bitmap, error) {
+	return bitmapOp(x, y, Xor, XorRoaring)
+}
@@ -0,0 +1,258 @@
+// Package bloom implements Bloom filters on top of BitArray.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+
+	bitarray "github.com/minami14/go-bitarray"
+)
+
+// Hash returns two hashes of data. The indices of an element are derived
+// from them by double hashing, so both should be well mixed and uncorrelated.
+type Hash func(data []byte) (uint64, uint64)
+
+// DefaultHash is the 64-bit FNV-1a hash of data passed through two different finalizers,
+// the one of MurmurHash3 and the one of SplitMix64.
+func DefaultHash(data []byte) (uint64, uint64) {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	sum := h.Sum64()
+	return murmurMix(sum), splitMix(sum)
+}
+
+// murmurMix is the finalizer of MurmurHash3.
+func murmurMix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// splitMix is the finalizer of SplitMix64.
+func splitMix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// Filter is a Bloom filter, a probabilistic set that may report false positives but never false negatives.
+type Filter struct {
+	bits *bitarray.BitArray
+	k    int
+	hash Hash
+}
+
+// New is Filter constructed with m bits and k hash functions.
+func New(m, k int) (*Filter, error) {
+	if m <= 0 || k <= 0 {
+		return nil, errors.New("non-positive size argument")
+	}
+
+	bits, err := bitarray.NewBitArray(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Filter{
+		bits: bits,
+		k:    k,
+		hash: DefaultHash,
+	}, nil
+}
+
+// NewWithEstimates is Filter constructed to hold n items with a false positive rate of p.
+func NewWithEstimates(n int, p float64) (*Filter, error) {
+	m, k, err := EstimateParameters(n, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(m, k)
+}
+
+// EstimateParameters returns the optimal number of bits and hash functions
+// for n items and a false positive rate of p.
+func EstimateParameters(n int, p float64) (int, int, error) {
+	if n <= 0 {
+		return 0, 0, errors.New("non-positive item count argument")
+	}
+
+	if !(p > 0 && p < 1) {
+		return 0, 0, errors.New("false positive rate out of range")
+	}
+
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+	if k < 1 {
+		k = 1
+	}
+
+	return int(m), int(k), nil
+}
+
+// SetHash sets the hash function. It must be set before any item is added.
+func (f *Filter) SetHash(hash Hash) {
+	f.hash = hash
+}
+
+// Cap returns the number of bits of the Filter.
+func (f *Filter) Cap() int {
+	return f.bits.Length()
+}
+
+// K returns the number of hash functions of the Filter.
+func (f *Filter) K() int {
+	return f.k
+}
+
+// locations calls fn with each index of data until it returns false,
+// and reports whether it always returned true.
+func (f *Filter) locations(data []byte, fn func(index int) bool) bool {
+	return locations(f.hash, f.k, f.bits.Length(), data, fn)
+}
+
+// locations calls fn with each of the k indices of data among m by double
+// hashing until it returns false, and reports whether it always returned true.
+func locations(hash Hash, k, m int, data []byte, fn func(index int) bool) bool {
+	h1, h2 := hash(data)
+	for i := 0; i < k; i++ {
+		if !fn(int((h1 + uint64(i)*h2) % uint64(m))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Add adds data to the Filter.
+func (f *Filter) Add(data []byte) {
+	f.locations(data, func(index int) bool {
+		_ = f.bits.Set(index)
+		return true
+	})
+}
+
+// Test reports whether data may be in the Filter.
+func (f *Filter) Test(data []byte) bool {
+	return f.locations(data, func(index int) bool {
+		v, _ := f.bits.Get(index)
+		return v
+	})
+}
+
+// TestAndAdd reports whether data may be in the Filter, then adds it.
+func (f *Filter) TestAndAdd(data []byte) bool {
+	present := true
+	f.locations(data, func(index int) bool {
+		if v, _ := f.bits.Get(index); !v {
+			present = false
+			_ = f.bits.Set(index)
+		}
+
+		return true
+	})
+
+	return present
+}
+
+// compatible checks that x has the same size and number of hash functions.
+func (f *Filter) compatible(x *Filter) error {
+	if f.bits.Length() != x.bits.Length() || f.k != x.k {
+		return errors.New("incompatible bloom filters")
+	}
+
+	return nil
+}
+
+// Union adds the items of x, which must have the same size and number of hash functions.
+func (f *Filter) Union(x *Filter) error {
+	if err := f.compatible(x); err != nil {
+		return err
+	}
+
+	bits, err := bitarray.Or(f.bits, x.bits)
+	if err != nil {
+		return err
+	}
+
+	f.bits = bits
+	return nil
+}
+
+// Intersection keeps only the items that may also be in x, which must
+// have the same size and number of hash functions.
+func (f *Filter) Intersection(x *Filter) error {
+	if err := f.compatible(x); err != nil {
+		return err
+	}
+
+	bits, err := bitarray.And(f.bits, x.bits)
+	if err != nil {
+		return err
+	}
+
+	f.bits = bits
+	return nil
+}
+
+// EstimatedCount returns an estimate of the number of items added,
+// derived from the number of set bits.
+func (f *Filter) EstimatedCount() float64 {
+	m := float64(f.bits.Length())
+	x := float64(f.bits.OnesCount())
+	return -m / float64(f.k) * math.Log(1-x/m)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// The encoding is the number of hash functions as a little-endian uint64
+// followed by the binary encoding of the BitArray. The hash function is
+// not encoded.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	bits, err := f.bits.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 8, 8+len(bits))
+	binary.LittleEndian.PutUint64(data, uint64(f.k))
+	return append(data, bits...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// The hash function is kept, or DefaultHash if none is set.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("binary data too short")
+	}
+
+	k := binary.LittleEndian.Uint64(data)
+	if k == 0 || k > math.MaxInt32 {
+		return errors.New("hash function count out of range")
+	}
+
+	bits := &bitarray.BitArray{}
+	if err := bits.UnmarshalBinary(data[8:]); err != nil {
+		return err
+	}
+
+	if bits.Length() == 0 {
+		return errors.New("empty bloom filter")
+	}
+
+	f.bits = bits
+	f.k = int(k)
+	if f.hash == nil {
+		f.hash = DefaultHash
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package bloom
+
+import (
+	"math/bits"
+	"strconv"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	const n = 10000
+	const p = 0.01
+
+	f, err := NewWithEstimates(n, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < n; i++ {
+		if f.TestAndAdd([]byte(strconv.Itoa(i))) && i < 10 {
+			t.Errorf("unexpected positive %v", i)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if !f.Test([]byte(strconv.Itoa(i))) {
+			t.Errorf("false negative %v", i)
+		}
+	}
+
+	// The tolerance is about three standard deviations of the measured rate.
+	falsePositives := 0
+	for i := n; i < 11*n; i++ {
+		if f.Test([]byte(strconv.Itoa(i))) {
+			falsePositives++
+		}
+	}
+
+	if rate := float64(falsePositives) / (10 * n); rate > 1.1*p {
+		t.Errorf("false positive rate is too high %v", rate)
+	}
+
+	if count := f.EstimatedCount(); count < 0.9*n || count > 1.1*n {
+		t.Errorf("estimated count is out of range %v", count)
+	}
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unmarshaled := &Filter{}
+	if err := unmarshaled.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if unmarshaled.Cap() != f.Cap() || unmarshaled.K() != f.K() {
+		t.Errorf("size does not match %v %v", unmarshaled.Cap(), unmarshaled.K())
+	}
+
+	for i := 0; i < n; i++ {
+		if !unmarshaled.Test([]byte(strconv.Itoa(i))) {
+			t.Errorf("false negative after unmarshal %v", i)
+		}
+	}
+
+	for _, data := range [][]byte{nil, data[:8], append([]byte{0, 0, 0, 0, 0, 0, 0, 0}, data[8:]...)} {
+		if err := (&Filter{}).UnmarshalBinary(data); err == nil {
+			t.Error("expected error")
+		}
+	}
+}
+
+func TestFilter_Union(t *testing.T) {
+	x, err := New(1000, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	y, err := New(1000, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x.Add([]byte("a"))
+	x.Add([]byte("b"))
+	y.Add([]byte("b"))
+	y.Add([]byte("c"))
+
+	union, err := New(1000, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := union.Union(x); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := union.Union(y); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"a", "b", "c"} {
+		if !union.Test([]byte(s)) {
+			t.Errorf("false negative %v", s)
+		}
+	}
+
+	if err := x.Intersection(y); err != nil {
+		t.Fatal(err)
+	}
+
+	if !x.Test([]byte("b")) {
+		t.Error("false negative b")
+	}
+
+	if x.Test([]byte("a")) && x.Test([]byte("c")) {
+		t.Error("intersection kept both a and c")
+	}
+
+	z, err := New(1000, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := x.Union(z); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestFilter_SetHash(t *testing.T) {
+	f, err := New(64, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	f.SetHash(func(data []byte) (uint64, uint64) {
+		calls++
+		return 1, 2
+	})
+
+	f.Add([]byte("x"))
+	for _, index := range []int{1, 3, 5} {
+		if v, _ := f.bits.Get(index); !v {
+			t.Errorf("bit %v is not set", index)
+		}
+	}
+
+	if f.bits.OnesCount() != 3 || calls != 1 {
+		t.Errorf("unexpected state %v %v", f.bits.OnesCount(), calls)
+	}
+
+	if _, err := New(0, 1); err == nil {
+		t.Error("expected error")
+	}
+
+	if _, err := NewWithEstimates(10, 1); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDefaultHash(t *testing.T) {
+	// Similar keys must give hashes differing in about half of their bits.
+	for i := 0; i < 100; i++ {
+		x1, x2 := DefaultHash([]byte(strconv.Itoa(i)))
+		y1, y2 := DefaultHash([]byte(strconv.Itoa(i + 1)))
+		for _, d := range []int{bits.OnesCount64(x1 ^ y1), bits.OnesCount64(x2 ^ y2), bits.OnesCount64(x1 ^ x2)} {
+			if d < 12 || d > 52 {
+				t.Errorf("hashes are poorly mixed %v %v", i, d)
+			}
+		}
+	}
+}
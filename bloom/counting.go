@@ -0,0 +1,111 @@
+package bloom
+
+import (
+	"errors"
+)
+
+const (
+	counterBits     = 4
+	counterMax      = 1<<counterBits - 1
+	countersPerWord = 64 / counterBits
+)
+
+// CountingFilter is a Bloom filter with a 4-bit counter instead of a bit per
+// slot, so that items can be removed. A counter that reaches 15 saturates and
+// is never decremented again, which avoids false negatives at the cost of
+// keeping its slot set.
+type CountingFilter struct {
+	counters []uint64
+	m        int
+	k        int
+	hash     Hash
+}
+
+// NewCountingFilter is CountingFilter constructed with m counters and k hash functions.
+func NewCountingFilter(m, k int) (*CountingFilter, error) {
+	if m <= 0 || k <= 0 {
+		return nil, errors.New("non-positive size argument")
+	}
+
+	return &CountingFilter{
+		counters: make([]uint64, (m+countersPerWord-1)/countersPerWord),
+		m:        m,
+		k:        k,
+		hash:     DefaultHash,
+	}, nil
+}
+
+// NewCountingFilterWithEstimates is CountingFilter constructed to hold n items with a false positive rate of p.
+func NewCountingFilterWithEstimates(n int, p float64) (*CountingFilter, error) {
+	m, k, err := EstimateParameters(n, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCountingFilter(m, k)
+}
+
+// SetHash sets the hash function. It must be set before any item is added.
+func (f *CountingFilter) SetHash(hash Hash) {
+	f.hash = hash
+}
+
+// Cap returns the number of counters of the CountingFilter.
+func (f *CountingFilter) Cap() int {
+	return f.m
+}
+
+// K returns the number of hash functions of the CountingFilter.
+func (f *CountingFilter) K() int {
+	return f.k
+}
+
+// counter returns the value of the specified counter.
+func (f *CountingFilter) counter(index int) uint64 {
+	shift := uint64(index%countersPerWord) * counterBits
+	return f.counters[index/countersPerWord] >> shift & counterMax
+}
+
+// add adds delta to the specified counter unless it is saturated,
+// or zero and delta is negative.
+func (f *CountingFilter) add(index int, delta int) {
+	c := f.counter(index)
+	if c == counterMax || (c == 0 && delta < 0) {
+		return
+	}
+
+	shift := uint64(index%countersPerWord) * counterBits
+	word := &f.counters[index/countersPerWord]
+	*word = *word&^(counterMax<<shift) | (uint64(int(c)+delta) << shift)
+}
+
+// Add adds data to the CountingFilter.
+func (f *CountingFilter) Add(data []byte) {
+	locations(f.hash, f.k, f.m, data, func(index int) bool {
+		f.add(index, 1)
+		return true
+	})
+}
+
+// Test reports whether data may be in the CountingFilter.
+func (f *CountingFilter) Test(data []byte) bool {
+	return locations(f.hash, f.k, f.m, data, func(index int) bool {
+		return f.counter(index) != 0
+	})
+}
+
+// Remove removes data from the CountingFilter. It fails without changing
+// anything if data is not in the CountingFilter. Removing an item that was
+// not added, but tests positive, may cause false negatives for other items.
+func (f *CountingFilter) Remove(data []byte) error {
+	if !f.Test(data) {
+		return errors.New("item not in filter")
+	}
+
+	locations(f.hash, f.k, f.m, data, func(index int) bool {
+		f.add(index, -1)
+		return true
+	})
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package bloom
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestCountingFilter(t *testing.T) {
+	const n = 1000
+
+	f, err := NewCountingFilterWithEstimates(n, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < n; i++ {
+		f.Add([]byte(strconv.Itoa(i)))
+	}
+
+	for i := 0; i < n; i += 2 {
+		if err := f.Remove([]byte(strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 1; i < n; i += 2 {
+		if !f.Test([]byte(strconv.Itoa(i))) {
+			t.Errorf("false negative %v", i)
+		}
+	}
+
+	present := 0
+	for i := 0; i < n; i += 2 {
+		if f.Test([]byte(strconv.Itoa(i))) {
+			present++
+		}
+	}
+
+	if present > n/20 {
+		t.Errorf("too many removed items test positive %v", present)
+	}
+
+	if err := f.Remove([]byte("absent")); err == nil && !f.Test([]byte("absent")) {
+		t.Error("expected error")
+	}
+}
+
+func TestCountingFilter_Saturate(t *testing.T) {
+	f, err := NewCountingFilter(10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.SetHash(func(data []byte) (uint64, uint64) {
+		return uint64(len(data)), 0
+	})
+
+	for i := 0; i < 20; i++ {
+		f.Add([]byte("a"))
+	}
+
+	f.Add([]byte("b"))
+	if f.counter(1) != counterMax || f.counter(0) != 0 || f.counter(2) != 0 {
+		t.Errorf("unexpected counters %v %v %v", f.counter(0), f.counter(1), f.counter(2))
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := f.Remove([]byte("a")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !f.Test([]byte("b")) {
+		t.Error("saturated counter was decremented")
+	}
+
+	if f.Cap() != 10 || f.K() != 1 {
+		t.Errorf("size does not match %v %v", f.Cap(), f.K())
+	}
+}
+
+func TestCountingFilter_RemoveFalsePositive(t *testing.T) {
+	f, err := NewCountingFilter(16, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashes := map[string][2]uint64{
+		"a": {3, 0},
+		"b": {3, 1},
+		"c": {5, 1},
+	}
+
+	f.SetHash(func(data []byte) (uint64, uint64) {
+		h := hashes[string(data)]
+		return h[0], h[1]
+	})
+
+	f.Add([]byte("b"))
+	if f.Test([]byte("c")) {
+		t.Fatal("unexpected false positive")
+	}
+
+	// Both locations of "a" hit the counter set by "b", which goes to zero and must stay there.
+	if err := f.Remove([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Test([]byte("c")) {
+		t.Error("removal changed unrelated counters")
+	}
+
+	for i := 0; i < 16; i++ {
+		if i != 4 && f.counter(i) != 0 {
+			t.Errorf("value does not match %v %v", i, f.counter(i))
+		}
+	}
+}
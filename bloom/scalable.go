@@ -0,0 +1,120 @@
+package bloom
+
+import (
+	"errors"
+)
+
+const (
+	// scalableGrowth is the factor by which the capacity of each new filter grows.
+	scalableGrowth = 2
+	// scalableTightening is the factor by which the false positive rate of each new filter shrinks.
+	scalableTightening = 0.8
+)
+
+// ScalableFilter is a Bloom filter that grows with the number of items.
+//
+// It chains Filters, adding a new one twice as large whenever the last one
+// holds its estimated capacity. The false positive rate of the i-th Filter
+// is p(1-r)r^i with r = 0.8. These rates sum to p, so with a well-mixed hash
+// the overall rate stays below p however many items are added.
+type ScalableFilter struct {
+	filters  []*Filter
+	capacity int
+	count    int
+	n        int
+	p        float64
+	hash     Hash
+}
+
+// NewScalableFilter is ScalableFilter constructed with an initial capacity
+// of n items and an overall false positive rate of p.
+func NewScalableFilter(n int, p float64) (*ScalableFilter, error) {
+	if _, _, err := EstimateParameters(n, p); err != nil {
+		return nil, err
+	}
+
+	f := &ScalableFilter{
+		n:    n,
+		p:    p,
+		hash: DefaultHash,
+	}
+
+	if err := f.grow(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// grow appends a new Filter.
+func (f *ScalableFilter) grow() error {
+	i := len(f.filters)
+	capacity := f.n
+	p := f.p * (1 - scalableTightening)
+	for j := 0; j < i; j++ {
+		capacity *= scalableGrowth
+		p *= scalableTightening
+	}
+
+	if capacity <= 0 {
+		return errors.New("capacity overflows int")
+	}
+
+	filter, err := NewWithEstimates(capacity, p)
+	if err != nil {
+		return err
+	}
+
+	filter.SetHash(f.hash)
+	f.filters = append(f.filters, filter)
+	f.capacity = capacity
+	f.count = 0
+	return nil
+}
+
+// SetHash sets the hash function. It must be set before any item is added.
+func (f *ScalableFilter) SetHash(hash Hash) {
+	f.hash = hash
+	for _, filter := range f.filters {
+		filter.SetHash(hash)
+	}
+}
+
+// Filters returns the number of chained Filters.
+func (f *ScalableFilter) Filters() int {
+	return len(f.filters)
+}
+
+// Add adds data to the ScalableFilter unless it may already be in it.
+func (f *ScalableFilter) Add(data []byte) error {
+	_, err := f.TestAndAdd(data)
+	return err
+}
+
+// Test reports whether data may be in the ScalableFilter.
+func (f *ScalableFilter) Test(data []byte) bool {
+	for _, filter := range f.filters {
+		if filter.Test(data) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TestAndAdd reports whether data may be in the ScalableFilter, and adds it if not.
+func (f *ScalableFilter) TestAndAdd(data []byte) (bool, error) {
+	if f.Test(data) {
+		return true, nil
+	}
+
+	if f.count >= f.capacity {
+		if err := f.grow(); err != nil {
+			return false, err
+		}
+	}
+
+	f.filters[len(f.filters)-1].Add(data)
+	f.count++
+	return false, nil
+}
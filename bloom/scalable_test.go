@@ -0,0 +1,49 @@
+package bloom
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestScalableFilter(t *testing.T) {
+	const p = 0.01
+
+	for _, n := range []int{1000, 10000} {
+		f, err := NewScalableFilter(n, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 20*n; i++ {
+			if err := f.Add([]byte(strconv.Itoa(i))); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if f.Filters() < 4 {
+			t.Errorf("filter did not grow %v %v", n, f.Filters())
+		}
+
+		for i := 0; i < 20*n; i++ {
+			if !f.Test([]byte(strconv.Itoa(i))) {
+				t.Errorf("false negative %v %v", n, i)
+			}
+		}
+
+		const queries = 100000
+		falsePositives := 0
+		for i := 20 * n; i < 20*n+queries; i++ {
+			if f.Test([]byte(strconv.Itoa(i))) {
+				falsePositives++
+			}
+		}
+
+		if rate := float64(falsePositives) / queries; rate > p {
+			t.Errorf("false positive rate is too high %v %v", n, rate)
+		}
+	}
+
+	if _, err := NewScalableFilter(0, p); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,119 @@
+package bitarray
+
+import (
+	"math/bits"
+)
+
+// Equal reports whether the BitArray has the same length and bits as x.
+func (b *BitArray) Equal(x *BitArray) bool {
+	if b.length != x.length {
+		return false
+	}
+
+	return equalWords(b.used(), x.used())
+}
+
+// IsSubsetOf reports whether every bit set in the BitArray is also set in x.
+// The shorter BitArray is padded with zeros.
+func (b *BitArray) IsSubsetOf(x *BitArray) bool {
+	xs := x.used()
+	for i, u := range b.used() {
+		if u&^word(xs, i) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsSupersetOf reports whether every bit set in x is also set in the BitArray.
+// The shorter BitArray is padded with zeros.
+func (b *BitArray) IsSupersetOf(x *BitArray) bool {
+	return x.IsSubsetOf(b)
+}
+
+// Intersects reports whether the BitArray and x have a set bit in common.
+func (b *BitArray) Intersects(x *BitArray) bool {
+	xs := x.used()
+	for i, u := range b.used() {
+		if u&word(xs, i) != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsDisjoint reports whether the BitArray and x have no set bit in common.
+func (b *BitArray) IsDisjoint(x *BitArray) bool {
+	return !b.Intersects(x)
+}
+
+// AndCount returns the number of one bits in the logical AND of two BitArrays.
+func AndCount(x, y *BitArray) int {
+	xs, ys := x.used(), y.used()
+	if len(xs) > len(ys) {
+		xs, ys = ys, xs
+	}
+
+	count := 0
+	for i, u := range xs {
+		count += bits.OnesCount64(u & ys[i])
+	}
+
+	return count
+}
+
+// OrCount returns the number of one bits in the logical OR of two BitArrays.
+func OrCount(x, y *BitArray) int {
+	xs, ys := x.used(), y.used()
+	if len(xs) > len(ys) {
+		xs, ys = ys, xs
+	}
+
+	count := 0
+	for i, u := range ys {
+		count += bits.OnesCount64(word(xs, i) | u)
+	}
+
+	return count
+}
+
+// XorCount returns the number of one bits in the exclusive OR of two BitArrays,
+// that is the Hamming distance between them.
+func XorCount(x, y *BitArray) int {
+	xs, ys := x.used(), y.used()
+	if len(xs) > len(ys) {
+		xs, ys = ys, xs
+	}
+
+	count := 0
+	for i, u := range ys {
+		count += bits.OnesCount64(word(xs, i) ^ u)
+	}
+
+	return count
+}
+
+// Jaccard returns the Jaccard similarity of two BitArrays, the number of bits
+// set in both divided by the number of bits set in either.
+// It is 1 if no bit is set in either.
+func Jaccard(x, y *BitArray) float64 {
+	xs, ys := x.used(), y.used()
+	if len(xs) > len(ys) {
+		xs, ys = ys, xs
+	}
+
+	and, or := 0, 0
+	for i, u := range ys {
+		v := word(xs, i)
+		and += bits.OnesCount64(v & u)
+		or += bits.OnesCount64(v | u)
+	}
+
+	if or == 0 {
+		return 1
+	}
+
+	return float64(and) / float64(or)
+}
@@ -0,0 +1,122 @@
+package bitarray
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBitArray_Equal(t *testing.T) {
+	for length := 0; length < 300; length += 7 {
+		x := newPatternBitArray(t, length, 7)
+		y := newPatternBitArray(t, length, 7)
+		if !x.Equal(y) {
+			t.Errorf("value does not match %v", length)
+		}
+
+		z := newPatternBitArray(t, length+1, 7)
+		if x.Equal(z) {
+			t.Errorf("length was ignored %v", length)
+		}
+
+		if length > 0 {
+			if err := y.Set(length - 1); err != nil {
+				t.Fatal(err)
+			}
+
+			if x.Equal(y) != ((length-1)%7 == 0) {
+				t.Errorf("value does not match %v", length)
+			}
+		}
+	}
+}
+
+func TestBitArray_IsSubsetOf(t *testing.T) {
+	for n := 0; n < 500; n++ {
+		x := newRandomBitArray(t, rand.Intn(300))
+		y := newRandomBitArray(t, rand.Intn(300))
+		if n%2 == 0 {
+			var err error
+			if y, err = Or(x, y); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		xs, ys := x.Bools(), y.Bools()
+		subset, superset, intersects := true, true, false
+		and, or, xor := 0, 0, 0
+		for i := 0; i < maxLength(len(xs), len(ys)); i++ {
+			u, v := bitAt(xs, i), bitAt(ys, i)
+			if u && !v {
+				subset = false
+			}
+
+			if v && !u {
+				superset = false
+			}
+
+			if u && v {
+				intersects = true
+				and++
+			}
+
+			if u || v {
+				or++
+			}
+
+			if u != v {
+				xor++
+			}
+		}
+
+		if x.IsSubsetOf(y) != subset {
+			t.Errorf("subset does not match %v %v", x, y)
+		}
+
+		if x.IsSupersetOf(y) != superset {
+			t.Errorf("superset does not match %v %v", x, y)
+		}
+
+		if x.Intersects(y) != intersects || x.IsDisjoint(y) == intersects {
+			t.Errorf("intersects does not match %v %v", x, y)
+		}
+
+		if AndCount(x, y) != and || AndCount(y, x) != and {
+			t.Errorf("and count does not match %v %v", AndCount(x, y), and)
+		}
+
+		if OrCount(x, y) != or || OrCount(y, x) != or {
+			t.Errorf("or count does not match %v %v", OrCount(x, y), or)
+		}
+
+		if XorCount(x, y) != xor || XorCount(y, x) != xor {
+			t.Errorf("xor count does not match %v %v", XorCount(x, y), xor)
+		}
+
+		jaccard := 1.0
+		if or > 0 {
+			jaccard = float64(and) / float64(or)
+		}
+
+		if Jaccard(x, y) != jaccard || Jaccard(y, x) != jaccard {
+			t.Errorf("jaccard does not match %v %v", Jaccard(x, y), jaccard)
+		}
+	}
+}
+
+func TestCompareAllocs(t *testing.T) {
+	x := newPatternBitArray(t, 10000, 3)
+	y := newPatternBitArray(t, 20000, 5)
+	allocs := testing.AllocsPerRun(10, func() {
+		x.Equal(y)
+		x.IsSubsetOf(y)
+		x.Intersects(y)
+		AndCount(x, y)
+		OrCount(x, y)
+		XorCount(x, y)
+		Jaccard(x, y)
+	})
+
+	if allocs != 0 {
+		t.Errorf("allocations %v", allocs)
+	}
+}
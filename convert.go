@@ -0,0 +1,150 @@
+package bitarray
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// BitOrder is the order in which the bits of a byte or a word map to indices of a BitArray.
+type BitOrder int
+
+const (
+	// LSBFirst maps the least significant bit to the lowest index, as the BitArray stores its bits.
+	LSBFirst BitOrder = iota
+	// MSBFirst maps the most significant bit to the lowest index, as in network order.
+	MSBFirst
+)
+
+// reverseBitsInBytes reverses the order of the bits in each byte of v.
+func reverseBitsInBytes(v uint64) uint64 {
+	return bits.ReverseBytes64(bits.Reverse64(v))
+}
+
+// FromBytes is BitArray constructed from bytes, with a length of 8 bits per byte.
+func FromBytes(data []byte, order BitOrder) (*BitArray, error) {
+	bitArray, err := NewBitArray(len(data) * 8)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bitArray.setBytes(data); err != nil {
+		return nil, err
+	}
+
+	if order == MSBFirst {
+		for i, v := range bitArray.blocks {
+			bitArray.blocks[i] = reverseBitsInBytes(v)
+		}
+	}
+
+	return bitArray, nil
+}
+
+// FromUint64s is BitArray constructed from words, with a length of 64 bits per word.
+func FromUint64s(words []uint64, order BitOrder) (*BitArray, error) {
+	bitArray, err := NewBitArray(len(words) * bitPerBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range words {
+		if order == MSBFirst {
+			v = bits.Reverse64(v)
+		}
+
+		bitArray.blocks[i] = v
+	}
+
+	return bitArray, nil
+}
+
+// FromBools is BitArray constructed from a bool slice.
+func FromBools(values []bool) (*BitArray, error) {
+	bitArray, err := NewBitArray(len(values))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range values {
+		if v {
+			bitArray.blocks[i/bitPerBlock] |= 1 << uint64(i%bitPerBlock)
+		}
+	}
+
+	return bitArray, nil
+}
+
+// FromString is BitArray constructed from a string of '0' and '1', starting at index 0.
+// It is the inverse of String.
+func FromString(s string) (*BitArray, error) {
+	bitArray, err := NewBitArray(len(s))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '0':
+		case '1':
+			bitArray.blocks[i/bitPerBlock] |= 1 << uint64(i%bitPerBlock)
+		default:
+			return nil, errors.New("invalid character in bit string")
+		}
+	}
+
+	return bitArray, nil
+}
+
+// Bytes returns the bits packed into ceil(length/8) bytes.
+// The unused bits of the last byte are zero.
+func (b *BitArray) Bytes(order BitOrder) []byte {
+	data := b.appendBytes(make([]byte, 0, byteSize(b.length)))
+	if order == MSBFirst {
+		for i, v := range data {
+			data[i] = bits.Reverse8(v)
+		}
+	}
+
+	return data
+}
+
+// Uint64s returns the bits packed into ceil(length/64) words.
+// The unused bits of the last word are zero.
+func (b *BitArray) Uint64s(order BitOrder) []uint64 {
+	size := b.length / bitPerBlock
+	if b.length%bitPerBlock != 0 {
+		size++
+	}
+
+	words := make([]uint64, size)
+	for i := range words {
+		v := b.blocks[i]
+		if order == MSBFirst {
+			v = bits.Reverse64(v)
+		}
+
+		words[i] = v
+	}
+
+	return words
+}
+
+// Bools returns the bits as a bool slice.
+func (b *BitArray) Bools() []bool {
+	values := make([]bool, b.length)
+	for i := range values {
+		values[i] = b.blocks[i/bitPerBlock]>>uint64(i%bitPerBlock)&1 != 0
+	}
+
+	return values
+}
+
+// String returns the bits as a string of '0' and '1', starting at index 0.
+func (b *BitArray) String() string {
+	s := make([]byte, b.length)
+	for i := range s {
+		s[i] = '0' + byte(b.blocks[i/bitPerBlock]>>uint64(i%bitPerBlock)&1)
+	}
+
+	return string(s)
+}
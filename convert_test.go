@@ -0,0 +1,148 @@
+package bitarray
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFromBytes(t *testing.T) {
+	data := []byte{0x01, 0x80, 0xf0, 0x0f, 0x12, 0x34, 0x56, 0x78, 0x9a}
+	for _, order := range []BitOrder{LSBFirst, MSBFirst} {
+		for size := 0; size <= len(data); size++ {
+			bitArray, err := FromBytes(data[:size], order)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if bitArray.length != size*8 {
+				t.Errorf("length does not match %v %v", size, bitArray.length)
+			}
+
+			for i := 0; i < bitArray.length; i++ {
+				shift := uint(i % 8)
+				if order == MSBFirst {
+					shift = 7 - shift
+				}
+
+				expected := data[i/8]>>shift&1 != 0
+				v, err := bitArray.Get(i)
+				if err != nil {
+					t.Error(err)
+				}
+
+				if v != expected {
+					t.Errorf("value does not match %v %v %v", order, size, i)
+				}
+			}
+
+			if !bytes.Equal(bitArray.Bytes(order), data[:size]) {
+				t.Errorf("bytes does not match %v %v", order, size)
+			}
+		}
+	}
+
+	bitArray, err := FromString("1100000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(bitArray.Bytes(LSBFirst), []byte{0x03, 0x02}) {
+		t.Errorf("value does not match %x", bitArray.Bytes(LSBFirst))
+	}
+
+	if !bytes.Equal(bitArray.Bytes(MSBFirst), []byte{0xc0, 0x40}) {
+		t.Errorf("value does not match %x", bitArray.Bytes(MSBFirst))
+	}
+}
+
+func TestFromUint64s(t *testing.T) {
+	words := []uint64{1, 1 << 63, 0x0123456789abcdef}
+	for _, order := range []BitOrder{LSBFirst, MSBFirst} {
+		bitArray, err := FromUint64s(words, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if bitArray.length != len(words)*bitPerBlock {
+			t.Errorf("length does not match %v", bitArray.length)
+		}
+
+		for i := 0; i < bitArray.length; i++ {
+			shift := uint(i % bitPerBlock)
+			if order == MSBFirst {
+				shift = bitPerBlock - 1 - shift
+			}
+
+			expected := words[i/bitPerBlock]>>shift&1 != 0
+			v, err := bitArray.Get(i)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if v != expected {
+				t.Errorf("value does not match %v %v", order, i)
+			}
+		}
+
+		converted := bitArray.Uint64s(order)
+		for i := range words {
+			if converted[i] != words[i] {
+				t.Errorf("value does not match %v %v %x", order, i, converted[i])
+			}
+		}
+	}
+
+	bitArray, err := FromString("1000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	words = bitArray.Uint64s(MSBFirst)
+	if len(words) != 1 || words[0] != 1<<63|1<<54 {
+		t.Errorf("value does not match %x", words)
+	}
+}
+
+func TestFromBools(t *testing.T) {
+	for length := 0; length < 300; length++ {
+		values := make([]bool, length)
+		for i := 0; i < length; i += 7 {
+			values[i] = true
+		}
+
+		bitArray, err := FromBools(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		converted := bitArray.Bools()
+		if len(converted) != length {
+			t.Fatalf("length does not match %v %v", length, len(converted))
+		}
+
+		for i := 0; i < length; i++ {
+			v, err := bitArray.Get(i)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if v != values[i] || converted[i] != values[i] {
+				t.Errorf("value does not match %v %v", length, i)
+			}
+		}
+
+		s := bitArray.String()
+		fromString, err := FromString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if fromString.String() != s || len(s) != length {
+			t.Errorf("string does not match %v %v", length, s)
+		}
+	}
+
+	if _, err := FromString("0120"); err == nil {
+		t.Error("expected error")
+	}
+}
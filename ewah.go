@@ -0,0 +1,393 @@
+package bitarray
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// EWAH is a BitArray compressed with the Enhanced Word-Aligned Hybrid encoding.
+//
+// The blocks are stored as a sequence of marker words, each followed by
+// literal blocks. A marker word holds in bit 0 the bit of a run of blocks
+// that are all zero or all one, in bits 1 to 32 the number of blocks in the
+// run and in bits 33 to 63 the number of literal blocks following the marker.
+// Mostly empty or mostly full BitArrays compress to a few words.
+type EWAH struct {
+	words  []uint64
+	length int
+}
+
+const (
+	ewahMaxRun      uint64 = 1<<32 - 1
+	ewahMaxLiterals uint64 = 1<<31 - 1
+	ewahLiteralBit         = 33
+)
+
+// Compress compresses the BitArray.
+func (b *BitArray) Compress() *EWAH {
+	var z ewahBuilder
+	for _, u := range b.used() {
+		z.addWord(u, 1)
+	}
+
+	return z.ewah(b.length)
+}
+
+// Decompress restores the BitArray.
+func (e *EWAH) Decompress() (*BitArray, error) {
+	bitArray, err := NewBitArray(e.length)
+	if err != nil {
+		return nil, err
+	}
+
+	c := ewahCursor{words: e.words}
+	for i := 0; i < len(bitArray.blocks); {
+		c.load()
+		if c.fills > 0 {
+			n := c.fills
+			if n > len(bitArray.blocks)-i {
+				n = len(bitArray.blocks) - i
+			}
+
+			if c.fill {
+				for j := i; j < i+n; j++ {
+					bitArray.blocks[j] = max
+				}
+			}
+
+			c.fills -= n
+			i += n
+			continue
+		}
+
+		bitArray.blocks[i] = e.words[c.literal]
+		c.literal++
+		c.literals--
+		i++
+	}
+
+	return bitArray, nil
+}
+
+// Length returns number of bits in the EWAH.
+func (e *EWAH) Length() int {
+	return e.length
+}
+
+// Size returns number of words of the compressed representation.
+func (e *EWAH) Size() int {
+	return len(e.words)
+}
+
+// OnesCount returns the number of one bits in the EWAH.
+func (e *EWAH) OnesCount() int {
+	count := 0
+	for i := 0; i < len(e.words); {
+		marker := e.words[i]
+		if marker&1 != 0 {
+			count += int(marker>>1&ewahMaxRun) * bitPerBlock
+		}
+
+		literals := int(marker >> ewahLiteralBit)
+		for _, u := range e.words[i+1 : i+1+literals] {
+			count += bits.OnesCount64(u)
+		}
+
+		i += 1 + literals
+	}
+
+	return count
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// The encoding is a version byte, the length and the number of words as
+// little-endian uint64s and the words as little-endian uint64s.
+func (e *EWAH) MarshalBinary() ([]byte, error) {
+	data := make([]byte, binaryHeaderSize+8+8*len(e.words))
+	data[0] = binaryVersion
+	binary.LittleEndian.PutUint64(data[1:], uint64(e.length))
+	binary.LittleEndian.PutUint64(data[binaryHeaderSize:], uint64(len(e.words)))
+	for i, u := range e.words {
+		binary.LittleEndian.PutUint64(data[binaryHeaderSize+8+8*i:], u)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (e *EWAH) UnmarshalBinary(data []byte) error {
+	if len(data) < binaryHeaderSize+8 {
+		return errors.New("binary data too short")
+	}
+
+	if data[0] != binaryVersion {
+		return errors.New("unsupported binary version")
+	}
+
+	length, err := decodeLength(data[1:binaryHeaderSize])
+	if err != nil {
+		return err
+	}
+
+	data = data[binaryHeaderSize:]
+	size := binary.LittleEndian.Uint64(data)
+	data = data[8:]
+	if size != uint64(len(data)/8) || len(data)%8 != 0 {
+		return errors.New("binary data size does not match word count")
+	}
+
+	words := make([]uint64, size)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+
+	if err := validateEWAH(words, length); err != nil {
+		return err
+	}
+
+	e.words = words
+	e.length = length
+	return nil
+}
+
+// validateEWAH checks that the words encode exactly the blocks of length bits
+// and that no bit beyond the length is set.
+func validateEWAH(words []uint64, length int) error {
+	blocks := blockCount(length)
+	last := uint64(0)
+	for i := 0; i < len(words); {
+		marker := words[i]
+		run := marker >> 1 & ewahMaxRun
+		literals := marker >> ewahLiteralBit
+		if literals > uint64(len(words)-i-1) {
+			return errors.New("literal words out of range")
+		}
+
+		if run > uint64(blocks) || literals > uint64(blocks)-run {
+			return errors.New("words exceed length")
+		}
+
+		blocks -= int(run + literals)
+		if run > 0 && marker&1 != 0 {
+			last = max
+		} else if run > 0 {
+			last = 0
+		}
+
+		if literals > 0 {
+			last = words[i+int(literals)]
+		}
+
+		i += 1 + int(literals)
+	}
+
+	if blocks != 0 {
+		return errors.New("words do not match length")
+	}
+
+	mod := length % bitPerBlock
+	if mod != 0 && last>>uint64(mod) != 0 {
+		return errors.New("bits set beyond length")
+	}
+
+	return nil
+}
+
+// AndEWAH is the logical AND of two EWAHs.
+func AndEWAH(x, y *EWAH) *EWAH {
+	return ewahOp(x, y, func(u, v uint64) uint64 { return u & v })
+}
+
+// OrEWAH is the logical OR of two EWAHs.
+func OrEWAH(x, y *EWAH) *EWAH {
+	return ewahOp(x, y, func(u, v uint64) uint64 { return u | v })
+}
+
+// XorEWAH is the exclusive OR of two EWAHs.
+func XorEWAH(x, y *EWAH) *EWAH {
+	return ewahOp(x, y, func(u, v uint64) uint64 { return u ^ v })
+}
+
+// ewahOp combines two EWAHs block by block with op without decompressing them.
+// Runs are combined as a whole, and the literal blocks facing a run that
+// determines the result, such as a run of zeros for AND, are skipped.
+// The shorter EWAH is padded with zeros.
+func ewahOp(x, y *EWAH, op func(u, v uint64) uint64) *EWAH {
+	length := maxLength(x.length, y.length)
+	n := blockCount(length)
+
+	var z ewahBuilder
+	cx := ewahCursor{words: x.words}
+	cy := ewahCursor{words: y.words}
+	for i := 0; i < n; {
+		cx.load()
+		cy.load()
+		k := n - i
+		switch {
+		case cx.fills > 0 && cy.fills > 0:
+			k = minInt(k, cx.fills, cy.fills)
+			z.addWord(op(fillWord(cx.fill), fillWord(cy.fill)), k)
+			cx.fills -= k
+			cy.fills -= k
+		case cx.fills > 0:
+			k = minInt(k, cx.fills, cy.literals)
+			u := fillWord(cx.fill)
+			if op(u, 0) == op(u, max) {
+				z.addWord(op(u, 0), k)
+			} else {
+				for _, v := range cy.words[cy.literal : cy.literal+k] {
+					z.addWord(op(u, v), 1)
+				}
+			}
+
+			cx.fills -= k
+			cy.skip(k)
+		case cy.fills > 0:
+			k = minInt(k, cy.fills, cx.literals)
+			v := fillWord(cy.fill)
+			if op(0, v) == op(max, v) {
+				z.addWord(op(0, v), k)
+			} else {
+				for _, u := range cx.words[cx.literal : cx.literal+k] {
+					z.addWord(op(u, v), 1)
+				}
+			}
+
+			cy.fills -= k
+			cx.skip(k)
+		default:
+			k = minInt(k, cx.literals, cy.literals)
+			for j := 0; j < k; j++ {
+				z.addWord(op(cx.words[cx.literal+j], cy.words[cy.literal+j]), 1)
+			}
+
+			cx.skip(k)
+			cy.skip(k)
+		}
+
+		i += k
+	}
+
+	return z.ewah(length)
+}
+
+func fillWord(fill bool) uint64 {
+	if fill {
+		return max
+	}
+
+	return 0
+}
+
+func minInt(x int, ys ...int) int {
+	for _, y := range ys {
+		if y < x {
+			x = y
+		}
+	}
+
+	return x
+}
+
+// ewahCursor reads the runs and literal blocks of an EWAH.
+// After the last word it reads an endless run of zeros.
+type ewahCursor struct {
+	words    []uint64
+	next     int
+	fill     bool
+	fills    int
+	literals int
+	literal  int
+}
+
+// load reads markers until there is a remaining run or literal block.
+func (c *ewahCursor) load() {
+	for c.fills == 0 && c.literals == 0 {
+		if c.next >= len(c.words) {
+			c.fill = false
+			c.fills = maxInt
+			return
+		}
+
+		marker := c.words[c.next]
+		c.fill = marker&1 != 0
+		c.fills = int(marker >> 1 & ewahMaxRun)
+		c.literals = int(marker >> ewahLiteralBit)
+		c.literal = c.next + 1
+		c.next = c.literal + c.literals
+	}
+}
+
+// skip skips n literal blocks.
+func (c *ewahCursor) skip(n int) {
+	c.literal += n
+	c.literals -= n
+}
+
+// ewahBuilder appends blocks to the words of an EWAH.
+type ewahBuilder struct {
+	words  []uint64
+	marker int
+	used   bool
+}
+
+// addWord appends n copies of a block.
+func (z *ewahBuilder) addWord(u uint64, n int) {
+	if u == 0 || u == max {
+		z.addFill(u == max, n)
+		return
+	}
+
+	for i := 0; i < n; i++ {
+		z.addLiteral(u)
+	}
+}
+
+// addFill appends a run of n blocks.
+func (z *ewahBuilder) addFill(fill bool, n int) {
+	for n > 0 {
+		if z.used {
+			marker := z.words[z.marker]
+			run := marker >> 1 & ewahMaxRun
+			if marker>>ewahLiteralBit == 0 && run < ewahMaxRun && (run == 0 || (marker&1 != 0) == fill) {
+				k := n
+				if room := ewahMaxRun - run; uint64(k) > room {
+					k = int(room)
+				}
+
+				marker = (run + uint64(k)) << 1
+				if fill {
+					marker |= 1
+				}
+
+				z.words[z.marker] = marker
+				n -= k
+				continue
+			}
+		}
+
+		z.addMarker()
+	}
+}
+
+// addLiteral appends a literal block.
+func (z *ewahBuilder) addLiteral(u uint64) {
+	if !z.used || z.words[z.marker]>>ewahLiteralBit == ewahMaxLiterals {
+		z.addMarker()
+	}
+
+	z.words[z.marker] += 1 << ewahLiteralBit
+	z.words = append(z.words, u)
+}
+
+func (z *ewahBuilder) addMarker() {
+	z.marker = len(z.words)
+	z.used = true
+	z.words = append(z.words, 0)
+}
+
+func (z *ewahBuilder) ewah(length int) *EWAH {
+	return &EWAH{words: z.words, length: length}
+}
@@ -0,0 +1,161 @@
+package bitarray
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// newSparseBitArray returns a BitArray with a few random bits, a run of
+// ones and a region of random dense blocks.
+func newSparseBitArray(t *testing.T, length int, seed int64) *BitArray {
+	rand.Seed(seed)
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < length/500; i++ {
+		if err := bitArray.Set(rand.Intn(length)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if length > 1000 {
+		start := rand.Intn(length - 1000)
+		if err := bitArray.SetRange(start, start+rand.Intn(1000)); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := len(bitArray.blocks) / 2; i < len(bitArray.blocks)/2+5; i++ {
+			bitArray.blocks[i] = rand.Uint64()
+		}
+	}
+
+	if length > 0 {
+		if err := bitArray.Set(length - 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return bitArray
+}
+
+func TestBitArray_Compress(t *testing.T) {
+	lengths := []int{0, 1, 63, 64, 65, 1000, 100000}
+	for i, length := range lengths {
+		bitArray := newSparseBitArray(t, length, int64(i))
+		compressed := bitArray.Compress()
+		if compressed.Length() != length {
+			t.Errorf("length does not match %v %v", length, compressed.Length())
+		}
+
+		if compressed.OnesCount() != bitArray.OnesCount() {
+			t.Errorf("count does not match %v %v %v", length, compressed.OnesCount(), bitArray.OnesCount())
+		}
+
+		decompressed, err := compressed.Decompress()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkBools(t, "decompress", decompressed, bitArray.Bools())
+
+		data, err := compressed.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		unmarshaled := &EWAH{}
+		if err := unmarshaled.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+
+		decompressed, err = unmarshaled.Decompress()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkBools(t, "unmarshal", decompressed, bitArray.Bools())
+	}
+
+	bitArray, err := NewBitArray(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bitArray.SetRange(1000, 100000); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bitArray.Set(500000); err != nil {
+		t.Fatal(err)
+	}
+
+	if size := bitArray.Compress().Size(); size > 10 {
+		t.Errorf("compressed size is too large %v", size)
+	}
+}
+
+func TestEWAH_UnmarshalBinary(t *testing.T) {
+	bitArray := newSparseBitArray(t, 1000, 1)
+	data, err := bitArray.Compress().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := map[string][]byte{
+		"empty":     {},
+		"truncated": data[:len(data)-1],
+		"words":     data[:len(data)-8],
+		"version":   append([]byte{binaryVersion + 1}, data[1:]...),
+		"length":    append(append([]byte{binaryVersion, 0x4c, 0x04}, data[3:9]...), data[9:]...),
+		"stray":     append(append([]byte{}, data[:len(data)-1]...), 0x80),
+	}
+
+	for name, data := range invalid {
+		if err := (&EWAH{}).UnmarshalBinary(data); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
+
+func TestAndEWAH(t *testing.T) {
+	ops := map[string]func(x, y bool) bool{
+		"and": func(x, y bool) bool { return x && y },
+		"or":  func(x, y bool) bool { return x || y },
+		"xor": func(x, y bool) bool { return x != y },
+	}
+
+	funcs := map[string]func(x, y *EWAH) *EWAH{
+		"and": AndEWAH,
+		"or":  OrEWAH,
+		"xor": XorEWAH,
+	}
+
+	lengths := []int{0, 64, 65, 5000, 100000}
+	for name, op := range ops {
+		for i, xLength := range lengths {
+			for j, yLength := range lengths {
+				x := newSparseBitArray(t, xLength, int64(i))
+				y := newSparseBitArray(t, yLength, int64(j+10))
+				xs, ys := x.Bools(), y.Bools()
+				expected := make([]bool, maxLength(xLength, yLength))
+				for k := range expected {
+					expected[k] = op(bitAt(xs, k), bitAt(ys, k))
+				}
+
+				z := funcs[name](x.Compress(), y.Compress())
+				decompressed, err := z.Decompress()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				checkBools(t, name, decompressed, expected)
+
+				if err := validateEWAH(z.words, z.length); err != nil {
+					t.Errorf("%v: invalid result %v", name, err)
+				}
+			}
+		}
+	}
+}
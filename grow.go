@@ -0,0 +1,111 @@
+package bitarray
+
+import (
+	"errors"
+)
+
+// The growable operations change the length of the BitArray in place.
+// Like append, they reserve extra capacity when the storage is reallocated,
+// so building a BitArray bit by bit takes amortized constant time per bit.
+// Bits beyond the length are always zero, also after shrinking.
+
+// Capacity returns the number of bits the BitArray can hold without reallocating its storage.
+func (b *BitArray) Capacity() int {
+	return cap(b.blocks) * bitPerBlock
+}
+
+// resize sets the length, zeroing the added bits and clearing the removed ones.
+func (b *BitArray) resize(length int) {
+	size := blockCount(length)
+	if size > len(b.blocks) {
+		b.blocks = append(b.blocks, make([]uint64, size-len(b.blocks))...)
+	} else {
+		b.blocks = b.blocks[:size]
+	}
+
+	b.length = length
+	b.clearTail()
+}
+
+// Grow increases the capacity of the BitArray, if necessary, to guarantee space for another n bits.
+func (b *BitArray) Grow(n int) error {
+	if n < 0 {
+		return errors.New("negative count argument")
+	}
+
+	if n > maxInt-b.length {
+		return errors.New("length overflows int")
+	}
+
+	size := blockCount(b.length + n)
+	if size <= cap(b.blocks) {
+		return nil
+	}
+
+	if size < 2*cap(b.blocks) {
+		size = 2 * cap(b.blocks)
+	}
+
+	blocks := make([]uint64, len(b.blocks), size)
+	copy(blocks, b.blocks)
+	b.blocks = blocks
+	return nil
+}
+
+// Resize sets the length of the BitArray.
+// Added bits are false and removed bits are discarded.
+func (b *BitArray) Resize(length int) error {
+	if length < 0 {
+		return errors.New("negative length argument")
+	}
+
+	b.resize(length)
+	return nil
+}
+
+// Truncate discards all but the first n bits.
+func (b *BitArray) Truncate(n int) error {
+	if n < 0 || n > b.length {
+		return errors.New("index out of range")
+	}
+
+	b.resize(n)
+	return nil
+}
+
+// PushBack appends a bit to the end of the BitArray.
+func (b *BitArray) PushBack(v bool) {
+	index := b.length
+	b.resize(b.length + 1)
+	if v {
+		b.blocks[index/bitPerBlock] |= 1 << uint64(index%bitPerBlock)
+	}
+}
+
+// AppendInPlace appends the bits of elem to the end of the BitArray.
+// Unlike Append it does not construct a new BitArray.
+func (b *BitArray) AppendInPlace(elem *BitArray) error {
+	if elem.length > maxInt-b.length {
+		return errors.New("length overflows int")
+	}
+
+	blocks := elem.used()
+	if elem == b {
+		blocks = append([]uint64(nil), blocks...)
+	}
+
+	offset := b.length
+	b.resize(b.length + elem.length)
+
+	i := offset / bitPerBlock
+	shift := uint64(offset % bitPerBlock)
+	for _, u := range blocks {
+		b.blocks[i] |= u << shift
+		i++
+		if shift != 0 && i < len(b.blocks) {
+			b.blocks[i] |= u >> (bitPerBlock - shift)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package bitarray
+
+import (
+	"testing"
+)
+
+func TestBitArray_PushBack(t *testing.T) {
+	bitArray := &BitArray{}
+	var expected []bool
+	allocs := 0
+	for i := 0; i < 10000; i++ {
+		capacity := bitArray.Capacity()
+		bitArray.PushBack(i%7 == 0)
+		expected = append(expected, i%7 == 0)
+		if bitArray.Capacity() != capacity {
+			allocs++
+		}
+	}
+
+	checkBools(t, "push back", bitArray, expected)
+
+	if allocs > 20 {
+		t.Errorf("too many reallocations %v", allocs)
+	}
+}
+
+func TestBitArray_Resize(t *testing.T) {
+	for length := 0; length < 200; length++ {
+		for _, n := range []int{0, 1, 63, 64, 65, 130, 300} {
+			bitArray := newPatternBitArray(t, length, 1)
+			if err := bitArray.Resize(n); err != nil {
+				t.Fatal(err)
+			}
+
+			expected := make([]bool, n)
+			for i := 0; i < n && i < length; i++ {
+				expected[i] = true
+			}
+
+			checkBools(t, "resize", bitArray, expected)
+
+			if n <= length {
+				bitArray = newPatternBitArray(t, length, 1)
+				if err := bitArray.Truncate(n); err != nil {
+					t.Fatal(err)
+				}
+
+				checkBools(t, "truncate", bitArray, expected)
+
+				if err := bitArray.Resize(length); err != nil {
+					t.Fatal(err)
+				}
+
+				expected = append(expected, make([]bool, length-n)...)
+				checkBools(t, "regrow", bitArray, expected)
+			}
+		}
+	}
+
+	bitArray := newPatternBitArray(t, 10, 1)
+	if err := bitArray.Truncate(11); err == nil {
+		t.Error("expected error")
+	}
+
+	if err := bitArray.Resize(-1); err == nil {
+		t.Error("expected error")
+	}
+
+	if err := bitArray.Grow(-1); err == nil {
+		t.Error("expected error")
+	}
+
+	if err := bitArray.Grow(1000); err != nil {
+		t.Fatal(err)
+	}
+
+	if bitArray.Capacity() < 1010 {
+		t.Errorf("capacity is too small %v", bitArray.Capacity())
+	}
+
+	blocks := bitArray.blocks
+	for i := 0; i < 1000; i++ {
+		bitArray.PushBack(true)
+	}
+
+	if &blocks[0] != &bitArray.blocks[0] {
+		t.Error("storage was reallocated")
+	}
+}
+
+func TestBitArray_AppendInPlace(t *testing.T) {
+	for _, length := range []int{0, 1, 63, 64, 65, 130} {
+		for _, n := range []int{0, 1, 63, 64, 65, 200} {
+			x := newPatternBitArray(t, length, 3)
+			y := newPatternBitArray(t, n, 5)
+			expected := append(x.Bools(), y.Bools()...)
+			if err := x.AppendInPlace(y); err != nil {
+				t.Fatal(err)
+			}
+
+			checkBools(t, "append", x, expected)
+		}
+
+		x := newPatternBitArray(t, length, 3)
+		expected := append(x.Bools(), x.Bools()...)
+		if err := x.AppendInPlace(x); err != nil {
+			t.Fatal(err)
+		}
+
+		checkBools(t, "append self", x, expected)
+	}
+}
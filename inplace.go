@@ -0,0 +1,200 @@
+package bitarray
+
+// The in-place operations reuse the storage of the receiver or of dst
+// instead of allocating a new BitArray.
+//
+// Methods on the receiver keep its length: bits of the argument beyond it
+// are ignored and missing bits of the argument are treated as zero.
+//
+// The Into functions set the length of dst to the length of the longer
+// operand, treating the missing bits of the shorter one as zero. dst may be
+// one of the operands.
+
+// word returns the i-th block of blocks, or zero if it is out of range.
+func word(blocks []uint64, i int) uint64 {
+	if i >= 0 && i < len(blocks) {
+		return blocks[i]
+	}
+
+	return 0
+}
+
+// used returns the blocks holding the bits within the length.
+func (b *BitArray) used() []uint64 {
+	return b.blocks[:blockCount(b.length)]
+}
+
+// reuse sets the length of the BitArray, reusing its storage when the capacity is sufficient.
+// The contents of the blocks are unspecified afterwards.
+func (b *BitArray) reuse(length int) {
+	size := blockCount(length)
+	if cap(b.blocks) < size {
+		b.blocks = make([]uint64, size)
+	} else {
+		b.blocks = b.blocks[:size]
+	}
+
+	b.length = length
+}
+
+// into prepares dst for the result of a binary operation on x and y and
+// returns the blocks of the operands as they were before dst changed.
+func into(dst, x, y *BitArray) ([]uint64, []uint64) {
+	xs, ys := x.used(), y.used()
+	length := x.length
+	if y.length > length {
+		length = y.length
+	}
+
+	dst.reuse(length)
+	return xs, ys
+}
+
+// InPlaceAnd sets the BitArray to the logical AND of itself and x.
+func (b *BitArray) InPlaceAnd(x *BitArray) {
+	combine(opAnd, b.used(), b.used(), x.used())
+}
+
+// InPlaceOr sets the BitArray to the logical OR of itself and x.
+func (b *BitArray) InPlaceOr(x *BitArray) {
+	combine(opOr, b.used(), b.used(), x.used())
+
+	b.clearTail()
+}
+
+// InPlaceXor sets the BitArray to the exclusive OR of itself and x.
+func (b *BitArray) InPlaceXor(x *BitArray) {
+	combine(opXor, b.used(), b.used(), x.used())
+
+	b.clearTail()
+}
+
+// InPlaceAndNot clears the bits of the BitArray that are set in x.
+// Note that AndNot instead clears the bits of its argument that are set in the receiver.
+func (b *BitArray) InPlaceAndNot(x *BitArray) {
+	combine(opAndNot, b.used(), b.used(), x.used())
+}
+
+// Flip inverts all bits in place.
+func (b *BitArray) Flip() {
+	notWords(b.used(), b.used())
+
+	b.clearTail()
+}
+
+// ShiftLeftInPlace shifts the bits n places towards the higher indices in place.
+// Unlike LeftShift the length is kept, so the bits shifted beyond it are discarded.
+// A negative n shifts to the right.
+func (b *BitArray) ShiftLeftInPlace(n int) {
+	if n < 0 {
+		// Any count of at least the length clears all bits, and clamping
+		// keeps the negation from overflowing.
+		if n < -b.length {
+			n = -b.length
+		}
+
+		b.shiftRight(-n)
+		return
+	}
+
+	b.shiftLeft(n)
+}
+
+// ShiftRightInPlace shifts the bits n places towards the lower indices in place.
+// A negative n shifts to the left.
+func (b *BitArray) ShiftRightInPlace(n int) {
+	if n < 0 {
+		if n < -b.length {
+			n = -b.length
+		}
+
+		b.shiftLeft(-n)
+		return
+	}
+
+	b.shiftRight(n)
+}
+
+// shiftLeft shifts the bits n places towards the higher indices, keeping the length.
+func (b *BitArray) shiftLeft(n int) {
+	blocks := b.used()
+	if n >= b.length {
+		for i := range blocks {
+			blocks[i] = 0
+		}
+
+		return
+	}
+
+	div := n / bitPerBlock
+	mod := uint64(n % bitPerBlock)
+	shift := bitPerBlock - mod
+	for i := len(blocks) - 1; i > div; i-- {
+		blocks[i] = blocks[i-div]<<mod | blocks[i-div-1]>>shift
+	}
+
+	blocks[div] = blocks[0] << mod
+	for i := 0; i < div; i++ {
+		blocks[i] = 0
+	}
+
+	b.clearTail()
+}
+
+// shiftRight shifts the bits n places towards the lower indices, keeping the length.
+func (b *BitArray) shiftRight(n int) {
+	blocks := b.used()
+	if n >= b.length {
+		for i := range blocks {
+			blocks[i] = 0
+		}
+
+		return
+	}
+
+	div := n / bitPerBlock
+	mod := uint64(n % bitPerBlock)
+	shift := bitPerBlock - mod
+	last := len(blocks) - 1 - div
+	for i := 0; i < last; i++ {
+		blocks[i] = blocks[i+div]>>mod | blocks[i+div+1]<<shift
+	}
+
+	blocks[last] = blocks[last+div] >> mod
+	for i := last + 1; i < len(blocks); i++ {
+		blocks[i] = 0
+	}
+}
+
+// AndInto sets dst to the logical AND of x and y.
+func AndInto(dst, x, y *BitArray) {
+	xs, ys := into(dst, x, y)
+	combine(opAnd, dst.blocks, xs, ys)
+}
+
+// OrInto sets dst to the logical OR of x and y.
+func OrInto(dst, x, y *BitArray) {
+	xs, ys := into(dst, x, y)
+	combine(opOr, dst.blocks, xs, ys)
+}
+
+// XorInto sets dst to the exclusive OR of x and y.
+func XorInto(dst, x, y *BitArray) {
+	xs, ys := into(dst, x, y)
+	combine(opXor, dst.blocks, xs, ys)
+}
+
+// AndNotInto sets dst to the bits of x that are not set in y.
+func AndNotInto(dst, x, y *BitArray) {
+	xs, ys := into(dst, x, y)
+	combine(opAndNot, dst.blocks, xs, ys)
+}
+
+// NotInto sets dst to the inverse of x.
+func NotInto(dst, x *BitArray) {
+	xs := x.used()
+	dst.reuse(x.length)
+	notWords(dst.blocks, xs)
+
+	dst.clearTail()
+}
@@ -0,0 +1,164 @@
+package bitarray
+
+import (
+	"testing"
+)
+
+func newPatternBitArray(t *testing.T, length, interval int) *BitArray {
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < length; i += interval {
+		if err := bitArray.Set(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return bitArray
+}
+
+func bitAt(values []bool, i int) bool {
+	return i >= 0 && i < len(values) && values[i]
+}
+
+func checkBools(t *testing.T, name string, bitArray *BitArray, expected []bool) {
+	t.Helper()
+	if bitArray.length != len(expected) {
+		t.Fatalf("%v: length does not match %v %v", name, bitArray.length, len(expected))
+	}
+
+	if len(bitArray.blocks) != blockCount(bitArray.length) {
+		t.Errorf("%v: block count does not match %v %v", name, len(bitArray.blocks), blockCount(bitArray.length))
+	}
+
+	for i, v := range bitArray.Bools() {
+		if v != expected[i] {
+			t.Errorf("%v: value does not match %v %v %v", name, len(expected), i, v)
+		}
+	}
+
+	if len(bitArray.blocks) > 0 && bitArray.length%bitPerBlock != 0 {
+		if bitArray.blocks[len(bitArray.blocks)-1]>>uint64(bitArray.length%bitPerBlock) != 0 {
+			t.Errorf("%v: bits set beyond length %v", name, bitArray.length)
+		}
+	}
+}
+
+func TestBitArray_InPlaceAnd(t *testing.T) {
+	ops := map[string]func(x, y bool) bool{
+		"and":    func(x, y bool) bool { return x && y },
+		"or":     func(x, y bool) bool { return x || y },
+		"xor":    func(x, y bool) bool { return x != y },
+		"andNot": func(x, y bool) bool { return x && !y },
+	}
+
+	inPlace := map[string]func(b, x *BitArray){
+		"and":    (*BitArray).InPlaceAnd,
+		"or":     (*BitArray).InPlaceOr,
+		"xor":    (*BitArray).InPlaceXor,
+		"andNot": (*BitArray).InPlaceAndNot,
+	}
+
+	intoFuncs := map[string]func(dst, x, y *BitArray){
+		"and":    AndInto,
+		"or":     OrInto,
+		"xor":    XorInto,
+		"andNot": AndNotInto,
+	}
+
+	lengths := []int{0, 1, 63, 64, 65, 100, 128, 200}
+	for name, op := range ops {
+		for _, xLength := range lengths {
+			for _, yLength := range lengths {
+				x := newPatternBitArray(t, xLength, 3)
+				y := newPatternBitArray(t, yLength, 5)
+				xs, ys := x.Bools(), y.Bools()
+
+				expected := make([]bool, xLength)
+				for i := range expected {
+					expected[i] = op(xs[i], bitAt(ys, i))
+				}
+
+				inPlace[name](x, y)
+				checkBools(t, name, x, expected)
+
+				length := xLength
+				if yLength > length {
+					length = yLength
+				}
+
+				expected = make([]bool, length)
+				for i := range expected {
+					expected[i] = op(bitAt(xs, i), bitAt(ys, i))
+				}
+
+				x = newPatternBitArray(t, xLength, 3)
+				dst := newPatternBitArray(t, 300, 1)
+				dst.reuse(1)
+				intoFuncs[name](dst, x, y)
+				checkBools(t, name+" into", dst, expected)
+
+				intoFuncs[name](x, x, y)
+				checkBools(t, name+" alias x", x, expected)
+
+				x = newPatternBitArray(t, xLength, 3)
+				intoFuncs[name](y, x, y)
+				checkBools(t, name+" alias y", y, expected)
+			}
+		}
+	}
+}
+
+func TestBitArray_Flip(t *testing.T) {
+	for length := 0; length < 300; length++ {
+		bitArray := newPatternBitArray(t, length, 7)
+		expected := bitArray.Bools()
+		for i := range expected {
+			expected[i] = !expected[i]
+		}
+
+		dst := &BitArray{}
+		NotInto(dst, bitArray)
+		checkBools(t, "not into", dst, expected)
+
+		bitArray.Flip()
+		checkBools(t, "flip", bitArray, expected)
+	}
+}
+
+func TestBitArray_ShiftLeftInPlace(t *testing.T) {
+	for length := 0; length < 300; length++ {
+		for n := -150; n < 150; n++ {
+			bitArray := newPatternBitArray(t, length, 7)
+			values := bitArray.Bools()
+
+			left := make([]bool, length)
+			for i := range left {
+				left[i] = bitAt(values, i-n)
+			}
+
+			bitArray.ShiftLeftInPlace(n)
+			checkBools(t, "left", bitArray, left)
+
+			bitArray = newPatternBitArray(t, length, 7)
+			bitArray.ShiftRightInPlace(-n)
+			checkBools(t, "right", bitArray, left)
+		}
+	}
+}
+
+func TestBitArray_ShiftInPlaceExtreme(t *testing.T) {
+	for _, length := range []int{0, 1, 64, 100} {
+		for _, n := range []int{-maxInt - 1, -maxInt, -length - 1, length + 1, maxInt} {
+			bitArray := newPatternBitArray(t, length, 3)
+			bitArray.ShiftLeftInPlace(n)
+			checkBools(t, "left", bitArray, make([]bool, length))
+
+			bitArray = newPatternBitArray(t, length, 3)
+			bitArray.ShiftRightInPlace(n)
+			checkBools(t, "right", bitArray, make([]bool, length))
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package bitarray
+
+import (
+	"math/bits"
+)
+
+// NextSet returns the index of the first set bit at or after from.
+// It returns false if there is no such bit.
+func (b *BitArray) NextSet(from int) (int, bool) {
+	if from < 0 {
+		from = 0
+	}
+
+	if from >= b.length {
+		return -1, false
+	}
+
+	i := from / bitPerBlock
+	u := b.blocks[i] >> uint64(from%bitPerBlock)
+	if u != 0 {
+		return from + bits.TrailingZeros64(u), true
+	}
+
+	for i++; i < len(b.blocks); i++ {
+		if b.blocks[i] != 0 {
+			return i*bitPerBlock + bits.TrailingZeros64(b.blocks[i]), true
+		}
+	}
+
+	return -1, false
+}
+
+// NextClear returns the index of the first clear bit at or after from.
+// It returns false if there is no such bit.
+func (b *BitArray) NextClear(from int) (int, bool) {
+	if from < 0 {
+		from = 0
+	}
+
+	if from >= b.length {
+		return -1, false
+	}
+
+	i := from / bitPerBlock
+	index := -1
+	u := ^b.blocks[i] >> uint64(from%bitPerBlock)
+	if u != 0 {
+		index = from + bits.TrailingZeros64(u)
+	} else {
+		for i++; i < len(b.blocks); i++ {
+			if b.blocks[i] != max {
+				index = i*bitPerBlock + bits.TrailingZeros64(^b.blocks[i])
+				break
+			}
+		}
+	}
+
+	if index < 0 || index >= b.length {
+		return -1, false
+	}
+
+	return index, true
+}
+
+// PrevSet returns the index of the last set bit at or before from.
+// It returns false if there is no such bit.
+func (b *BitArray) PrevSet(from int) (int, bool) {
+	if from >= b.length {
+		from = b.length - 1
+	}
+
+	if from < 0 {
+		return -1, false
+	}
+
+	i := from / bitPerBlock
+	u := b.blocks[i] << uint64(bitPerBlock-1-from%bitPerBlock)
+	if u != 0 {
+		return from - bits.LeadingZeros64(u), true
+	}
+
+	for i--; i >= 0; i-- {
+		if b.blocks[i] != 0 {
+			return i*bitPerBlock + bitPerBlock - 1 - bits.LeadingZeros64(b.blocks[i]), true
+		}
+	}
+
+	return -1, false
+}
+
+// PrevClear returns the index of the last clear bit at or before from.
+// It returns false if there is no such bit.
+func (b *BitArray) PrevClear(from int) (int, bool) {
+	if from >= b.length {
+		from = b.length - 1
+	}
+
+	if from < 0 {
+		return -1, false
+	}
+
+	i := from / bitPerBlock
+	u := ^b.blocks[i] << uint64(bitPerBlock-1-from%bitPerBlock)
+	if u != 0 {
+		return from - bits.LeadingZeros64(u), true
+	}
+
+	for i--; i >= 0; i-- {
+		if b.blocks[i] != max {
+			return i*bitPerBlock + bitPerBlock - 1 - bits.LeadingZeros64(^b.blocks[i]), true
+		}
+	}
+
+	return -1, false
+}
+
+// All returns an iterator over the indices and values of all bits.
+// It can be used with range over func:
+//
+//	for i, v := range b.All() {
+//	}
+func (b *BitArray) All() func(yield func(int, bool) bool) {
+	return func(yield func(int, bool) bool) {
+		for i := 0; i < b.length; i++ {
+			if !yield(i, b.blocks[i/bitPerBlock]>>uint64(i%bitPerBlock)&1 != 0) {
+				return
+			}
+		}
+	}
+}
+
+// SetBits returns an iterator over the indices of set bits in ascending order.
+// Blocks without set bits are skipped as a whole.
+func (b *BitArray) SetBits() func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i, u := range b.blocks {
+			for u != 0 {
+				if !yield(i*bitPerBlock + bits.TrailingZeros64(u)) {
+					return
+				}
+
+				u &= u - 1
+			}
+		}
+	}
+}
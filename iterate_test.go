@@ -0,0 +1,123 @@
+package bitarray
+
+import (
+	"testing"
+)
+
+func TestBitArray_NextSet(t *testing.T) {
+	for length := 0; length < 300; length++ {
+		for _, interval := range []int{1, 7, 100} {
+			bitArray, err := NewBitArray(length)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := interval / 2; i < length; i += interval {
+				if err := bitArray.Set(i); err != nil {
+					t.Error(err)
+				}
+			}
+
+			bools := bitArray.Bools()
+			for from := -2; from < length+2; from++ {
+				nextSet, nextClear, prevSet, prevClear := -1, -1, -1, -1
+				for i := from; i < length; i++ {
+					if i < 0 {
+						continue
+					}
+
+					if bools[i] && nextSet < 0 {
+						nextSet = i
+					}
+
+					if !bools[i] && nextClear < 0 {
+						nextClear = i
+					}
+				}
+
+				for i := from; i >= 0; i-- {
+					if i >= length {
+						continue
+					}
+
+					if bools[i] && prevSet < 0 {
+						prevSet = i
+					}
+
+					if !bools[i] && prevClear < 0 {
+						prevClear = i
+					}
+				}
+
+				if index, ok := bitArray.NextSet(from); index != nextSet || ok != (nextSet >= 0) {
+					t.Errorf("NextSet does not match %v %v %v %v", length, from, index, nextSet)
+				}
+
+				if index, ok := bitArray.NextClear(from); index != nextClear || ok != (nextClear >= 0) {
+					t.Errorf("NextClear does not match %v %v %v %v", length, from, index, nextClear)
+				}
+
+				if index, ok := bitArray.PrevSet(from); index != prevSet || ok != (prevSet >= 0) {
+					t.Errorf("PrevSet does not match %v %v %v %v", length, from, index, prevSet)
+				}
+
+				if index, ok := bitArray.PrevClear(from); index != prevClear || ok != (prevClear >= 0) {
+					t.Errorf("PrevClear does not match %v %v %v %v", length, from, index, prevClear)
+				}
+			}
+		}
+	}
+}
+
+func TestBitArray_SetBits(t *testing.T) {
+	for length := 0; length < 1000; length++ {
+		bitArray, err := NewBitArray(length)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < bitArray.length; i += 7 {
+			if err := bitArray.Set(i); err != nil {
+				t.Error(err)
+			}
+		}
+
+		expected := 0
+		bitArray.SetBits()(func(index int) bool {
+			if index != expected {
+				t.Errorf("value does not match %v %v %v", length, index, expected)
+			}
+
+			expected += 7
+			return true
+		})
+
+		if expected < length {
+			t.Errorf("iteration stopped early %v %v", length, expected)
+		}
+
+		count := 0
+		bitArray.All()(func(index int, v bool) bool {
+			if index != count || v != (index%7 == 0) {
+				t.Errorf("value does not match %v %v %v", length, index, v)
+			}
+
+			count++
+			return true
+		})
+
+		if count != length {
+			t.Errorf("count does not match %v %v", length, count)
+		}
+
+		count = 0
+		bitArray.SetBits()(func(index int) bool {
+			count++
+			return count < 3
+		})
+
+		if count > 3 {
+			t.Errorf("iteration did not stop %v %v", length, count)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package bitarray
+
+import (
+	"math/bits"
+)
+
+// The bulk operations on blocks are done by the kernels popcount, andWords,
+// orWords, xorWords, andNotWords, notWords and equalWords. They process the
+// leading words in assembly, with AVX-512 or AVX2 on amd64 depending on the
+// CPU and with NEON on arm64, and fall back to the generic versions below
+// for the remaining words.
+// Other architectures and the purego build tag use the generic versions only.
+//
+// The combining kernels process len(dst) words and require the operands to
+// have at least as many. dst may be one of the operands.
+
+func popcountGeneric(words []uint64) int {
+	count := 0
+	for _, u := range words {
+		count += bits.OnesCount64(u)
+	}
+
+	return count
+}
+
+func andWordsGeneric(dst, x, y []uint64) {
+	for i := range dst {
+		dst[i] = x[i] & y[i]
+	}
+}
+
+func orWordsGeneric(dst, x, y []uint64) {
+	for i := range dst {
+		dst[i] = x[i] | y[i]
+	}
+}
+
+func xorWordsGeneric(dst, x, y []uint64) {
+	for i := range dst {
+		dst[i] = x[i] ^ y[i]
+	}
+}
+
+func andNotWordsGeneric(dst, x, y []uint64) {
+	for i := range dst {
+		dst[i] = x[i] &^ y[i]
+	}
+}
+
+func notWordsGeneric(dst, x []uint64) {
+	for i := range dst {
+		dst[i] = ^x[i]
+	}
+}
+
+func equalWordsGeneric(x, y []uint64) bool {
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// combineOp is a bitwise operation done by combine.
+type combineOp int
+
+const (
+	opAnd combineOp = iota
+	opOr
+	opXor
+	opAndNot
+)
+
+// combine sets dst to the result of op on xs and ys padded with zeros.
+func combine(op combineOp, dst, xs, ys []uint64) {
+	n := minInt(len(dst), len(xs), len(ys))
+	switch op {
+	case opAnd:
+		andWords(dst[:n], xs, ys)
+	case opOr:
+		orWords(dst[:n], xs, ys)
+	case opXor:
+		xorWords(dst[:n], xs, ys)
+	case opAndNot:
+		andNotWords(dst[:n], xs, ys)
+	}
+
+	// Beyond the shorter operand the result is a copy of the longer one or zero.
+	var rest []uint64
+	switch {
+	case len(xs) > n && op != opAnd:
+		rest = xs[n:]
+	case len(ys) > n && (op == opOr || op == opXor):
+		rest = ys[n:]
+	}
+
+	m := n + copy(dst[n:], rest)
+	for i := m; i < len(dst); i++ {
+		dst[i] = 0
+	}
+}
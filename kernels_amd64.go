@@ -0,0 +1,171 @@
+//go:build !purego
+// +build !purego
+
+package bitarray
+
+// simdWords is the number of words processed by one iteration of the assembly kernels.
+const simdWords = 16
+
+var (
+	// hasAVX2 reports whether the CPU and the operating system support AVX2.
+	hasAVX2 bool
+	// hasAVX512 reports whether the CPU and the operating system support AVX-512F.
+	hasAVX512 bool
+	// hasAVX512POPCNT reports whether AVX-512 has the VPOPCNTQ instruction.
+	hasAVX512POPCNT bool
+)
+
+func init() {
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 7 {
+		return
+	}
+
+	_, _, ecx1, _ := cpuid(1, 0)
+	if ecx1&(1<<27) == 0 || ecx1&(1<<28) == 0 {
+		// No OSXSAVE or no AVX.
+		return
+	}
+
+	// The operating system must save the YMM and, for AVX-512, the opmask and ZMM registers.
+	xcr0, _ := xgetbv()
+	_, ebx7, ecx7, _ := cpuid(7, 0)
+	hasAVX2 = xcr0&0x6 == 0x6 && ebx7&(1<<5) != 0
+	hasAVX512 = hasAVX2 && xcr0&0xe6 == 0xe6 && ebx7&(1<<16) != 0
+	hasAVX512POPCNT = hasAVX512 && ecx7&(1<<14) != 0
+}
+
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+func xgetbv() (eax, edx uint32)
+
+//go:noescape
+func popcountAVX2(words *uint64, n int) int
+
+//go:noescape
+func popcountAVX512(words *uint64, n int) int
+
+//go:noescape
+func andAVX2(dst, x, y *uint64, n int)
+
+//go:noescape
+func orAVX2(dst, x, y *uint64, n int)
+
+//go:noescape
+func xorAVX2(dst, x, y *uint64, n int)
+
+//go:noescape
+func andNotAVX2(dst, x, y *uint64, n int)
+
+//go:noescape
+func notAVX2(dst, x *uint64, n int)
+
+//go:noescape
+func equalAVX2(x, y *uint64, n int) bool
+
+//go:noescape
+func andAVX512(dst, x, y *uint64, n int)
+
+//go:noescape
+func orAVX512(dst, x, y *uint64, n int)
+
+//go:noescape
+func xorAVX512(dst, x, y *uint64, n int)
+
+//go:noescape
+func andNotAVX512(dst, x, y *uint64, n int)
+
+//go:noescape
+func notAVX512(dst, x *uint64, n int)
+
+//go:noescape
+func equalAVX512(x, y *uint64, n int) bool
+
+// simdLength returns the number of leading words of n to be processed by the assembly kernels.
+func simdLength(n int) int {
+	if !hasAVX2 {
+		return 0
+	}
+
+	return n &^ (simdWords - 1)
+}
+
+func popcount(words []uint64) int {
+	n := simdLength(len(words))
+	count := 0
+	if n > 0 && hasAVX512POPCNT {
+		count = popcountAVX512(&words[0], n)
+	} else if n > 0 {
+		count = popcountAVX2(&words[0], n)
+	}
+
+	return count + popcountGeneric(words[n:])
+}
+
+func andWords(dst, x, y []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 && hasAVX512 {
+		andAVX512(&dst[0], &x[0], &y[0], n)
+	} else if n > 0 {
+		andAVX2(&dst[0], &x[0], &y[0], n)
+	}
+
+	andWordsGeneric(dst[n:], x[n:], y[n:])
+}
+
+func orWords(dst, x, y []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 && hasAVX512 {
+		orAVX512(&dst[0], &x[0], &y[0], n)
+	} else if n > 0 {
+		orAVX2(&dst[0], &x[0], &y[0], n)
+	}
+
+	orWordsGeneric(dst[n:], x[n:], y[n:])
+}
+
+func xorWords(dst, x, y []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 && hasAVX512 {
+		xorAVX512(&dst[0], &x[0], &y[0], n)
+	} else if n > 0 {
+		xorAVX2(&dst[0], &x[0], &y[0], n)
+	}
+
+	xorWordsGeneric(dst[n:], x[n:], y[n:])
+}
+
+func andNotWords(dst, x, y []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 && hasAVX512 {
+		andNotAVX512(&dst[0], &x[0], &y[0], n)
+	} else if n > 0 {
+		andNotAVX2(&dst[0], &x[0], &y[0], n)
+	}
+
+	andNotWordsGeneric(dst[n:], x[n:], y[n:])
+}
+
+func notWords(dst, x []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 && hasAVX512 {
+		notAVX512(&dst[0], &x[0], n)
+	} else if n > 0 {
+		notAVX2(&dst[0], &x[0], n)
+	}
+
+	notWordsGeneric(dst[n:], x[n:])
+}
+
+func equalWords(x, y []uint64) bool {
+	n := simdLength(len(x))
+	if n > 0 && hasAVX512 && !equalAVX512(&x[0], &y[0], n) {
+		return false
+	}
+
+	if n > 0 && !hasAVX512 && !equalAVX2(&x[0], &y[0], n) {
+		return false
+	}
+
+	return equalWordsGeneric(x[n:], y[n:])
+}
@@ -0,0 +1,373 @@
+// +build !purego
+
+#include "textflag.h"
+
+// Nibble popcount lookup table for VPSHUFB, repeated for both 128-bit lanes.
+DATA nibbleCount<>+0(SB)/8, $0x0302020102010100
+DATA nibbleCount<>+8(SB)/8, $0x0403030203020201
+DATA nibbleCount<>+16(SB)/8, $0x0302020102010100
+DATA nibbleCount<>+24(SB)/8, $0x0403030203020201
+GLOBL nibbleCount<>(SB), RODATA|NOPTR, $32
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	BYTE $0x0f; BYTE $0x01; BYTE $0xd0 // XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
+
+// NIBBLES replaces each byte of r with its popcount using the lookup table
+// in Y14 and the nibble mask in Y15. tmp is clobbered.
+#define NIBBLES(r, tmp) \
+	VPSRLW  $4, r, tmp; \
+	VPAND   Y15, r, r; \
+	VPAND   Y15, tmp, tmp; \
+	VPSHUFB r, Y14, r; \
+	VPSHUFB tmp, Y14, tmp; \
+	VPADDB  tmp, r, r
+
+// SUM256 stores the sum of the four quadwords of Y-register r in ret.
+#define SUM256(r, x, ret) \
+	VEXTRACTI128 $1, r, X0; \
+	VPADDQ       X0, x, X0; \
+	VPSHUFD      $0x4e, X0, X1; \
+	VPADDQ       X1, X0, X0; \
+	VMOVQ        X0, AX; \
+	MOVQ         AX, ret
+
+// func popcountAVX2(words *uint64, n int) int
+TEXT ·popcountAVX2(SB), NOSPLIT, $0-24
+	MOVQ words+0(FP), SI
+	MOVQ n+8(FP), CX
+	SHRQ $4, CX
+
+	VMOVDQU      nibbleCount<>(SB), Y14
+	MOVQ         $0x0f0f0f0f0f0f0f0f, AX
+	VMOVQ        AX, X15
+	VPBROADCASTQ X15, Y15
+	VPXOR        Y13, Y13, Y13
+	VPXOR        Y12, Y12, Y12
+
+popcountLoop:
+	VMOVDQU 0(SI), Y0
+	VMOVDQU 32(SI), Y1
+	VMOVDQU 64(SI), Y2
+	VMOVDQU 96(SI), Y3
+	NIBBLES(Y0, Y4)
+	NIBBLES(Y1, Y5)
+	NIBBLES(Y2, Y6)
+	NIBBLES(Y3, Y7)
+	VPADDB  Y1, Y0, Y0
+	VPADDB  Y3, Y2, Y2
+	VPADDB  Y2, Y0, Y0
+	VPSADBW Y13, Y0, Y0
+	VPADDQ  Y0, Y12, Y12
+	ADDQ    $128, SI
+	DECQ    CX
+	JNZ     popcountLoop
+
+	SUM256(Y12, X12, ret+16(FP))
+	VZEROUPPER
+	RET
+
+// func popcountAVX512(words *uint64, n int) int
+TEXT ·popcountAVX512(SB), NOSPLIT, $0-24
+	MOVQ words+0(FP), SI
+	MOVQ n+8(FP), CX
+	SHRQ $4, CX
+
+	VPXORQ Z2, Z2, Z2
+	VPXORQ Z3, Z3, Z3
+
+popcountLoop:
+	VMOVDQU64 0(SI), Z0
+	VMOVDQU64 64(SI), Z1
+	VPOPCNTQ  Z0, Z0
+	VPOPCNTQ  Z1, Z1
+	VPADDQ    Z0, Z2, Z2
+	VPADDQ    Z1, Z3, Z3
+	ADDQ      $128, SI
+	DECQ      CX
+	JNZ       popcountLoop
+
+	VPADDQ        Z3, Z2, Z2
+	VEXTRACTI64X4 $1, Z2, Y3
+	VPADDQ        Y3, Y2, Y2
+	SUM256(Y2, X2, ret+16(FP))
+	VZEROUPPER
+	RET
+
+// COMBINE sets 16 words at DI to op applied to the words at DX and SI.
+// The words at DX are the first operand of op, which VPANDN inverts.
+#define COMBINE(op) \
+	VMOVDQU 0(DX), Y0; \
+	VMOVDQU 32(DX), Y1; \
+	VMOVDQU 64(DX), Y2; \
+	VMOVDQU 96(DX), Y3; \
+	op      0(SI), Y0, Y0; \
+	op      32(SI), Y1, Y1; \
+	op      64(SI), Y2, Y2; \
+	op      96(SI), Y3, Y3; \
+	VMOVDQU Y0, 0(DI); \
+	VMOVDQU Y1, 32(DI); \
+	VMOVDQU Y2, 64(DI); \
+	VMOVDQU Y3, 96(DI); \
+	ADDQ    $128, SI; \
+	ADDQ    $128, DX; \
+	ADDQ    $128, DI
+
+// func andAVX2(dst, x, y *uint64, n int)
+TEXT ·andAVX2(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHRQ $4, CX
+
+andLoop:
+	COMBINE(VPAND)
+	DECQ CX
+	JNZ  andLoop
+	VZEROUPPER
+	RET
+
+// func orAVX2(dst, x, y *uint64, n int)
+TEXT ·orAVX2(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHRQ $4, CX
+
+orLoop:
+	COMBINE(VPOR)
+	DECQ CX
+	JNZ  orLoop
+	VZEROUPPER
+	RET
+
+// func xorAVX2(dst, x, y *uint64, n int)
+TEXT ·xorAVX2(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHRQ $4, CX
+
+xorLoop:
+	COMBINE(VPXOR)
+	DECQ CX
+	JNZ  xorLoop
+	VZEROUPPER
+	RET
+
+// func andNotAVX2(dst, x, y *uint64, n int)
+TEXT ·andNotAVX2(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHRQ $4, CX
+
+andNotLoop:
+	COMBINE(VPANDN)
+	DECQ CX
+	JNZ  andNotLoop
+	VZEROUPPER
+	RET
+
+// func notAVX2(dst, x *uint64, n int)
+TEXT ·notAVX2(SB), NOSPLIT, $0-24
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ n+16(FP), CX
+	SHRQ $4, CX
+
+	VPCMPEQQ Y15, Y15, Y15
+
+notLoop:
+	VPXOR   0(SI), Y15, Y0
+	VPXOR   32(SI), Y15, Y1
+	VPXOR   64(SI), Y15, Y2
+	VPXOR   96(SI), Y15, Y3
+	VMOVDQU Y0, 0(DI)
+	VMOVDQU Y1, 32(DI)
+	VMOVDQU Y2, 64(DI)
+	VMOVDQU Y3, 96(DI)
+	ADDQ    $128, SI
+	ADDQ    $128, DI
+	DECQ    CX
+	JNZ     notLoop
+	VZEROUPPER
+	RET
+
+// func equalAVX2(x, y *uint64, n int) bool
+TEXT ·equalAVX2(SB), NOSPLIT, $0-25
+	MOVQ x+0(FP), SI
+	MOVQ y+8(FP), DX
+	MOVQ n+16(FP), CX
+	SHRQ $4, CX
+
+equalLoop:
+	VMOVDQU 0(SI), Y0
+	VMOVDQU 32(SI), Y1
+	VMOVDQU 64(SI), Y2
+	VMOVDQU 96(SI), Y3
+	VPXOR   0(DX), Y0, Y0
+	VPXOR   32(DX), Y1, Y1
+	VPXOR   64(DX), Y2, Y2
+	VPXOR   96(DX), Y3, Y3
+	VPOR    Y1, Y0, Y0
+	VPOR    Y3, Y2, Y2
+	VPOR    Y2, Y0, Y0
+	VPTEST  Y0, Y0
+	JNZ     different
+	ADDQ    $128, SI
+	ADDQ    $128, DX
+	DECQ    CX
+	JNZ     equalLoop
+
+	MOVB $1, ret+24(FP)
+	VZEROUPPER
+	RET
+
+different:
+	MOVB $0, ret+24(FP)
+	VZEROUPPER
+	RET
+
+// COMBINE512 sets 16 words at DI to op applied to the words at DX and SI.
+// The words at DX are the first operand of op, which VPANDNQ inverts.
+#define COMBINE512(op) \
+	VMOVDQU64 0(DX), Z0; \
+	VMOVDQU64 64(DX), Z1; \
+	op        0(SI), Z0, Z0; \
+	op        64(SI), Z1, Z1; \
+	VMOVDQU64 Z0, 0(DI); \
+	VMOVDQU64 Z1, 64(DI); \
+	ADDQ      $128, SI; \
+	ADDQ      $128, DX; \
+	ADDQ      $128, DI
+
+// func andAVX512(dst, x, y *uint64, n int)
+TEXT ·andAVX512(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHRQ $4, CX
+
+andLoop:
+	COMBINE512(VPANDQ)
+	DECQ CX
+	JNZ  andLoop
+	VZEROUPPER
+	RET
+
+// func orAVX512(dst, x, y *uint64, n int)
+TEXT ·orAVX512(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHRQ $4, CX
+
+orLoop:
+	COMBINE512(VPORQ)
+	DECQ CX
+	JNZ  orLoop
+	VZEROUPPER
+	RET
+
+// func xorAVX512(dst, x, y *uint64, n int)
+TEXT ·xorAVX512(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHRQ $4, CX
+
+xorLoop:
+	COMBINE512(VPXORQ)
+	DECQ CX
+	JNZ  xorLoop
+	VZEROUPPER
+	RET
+
+// func andNotAVX512(dst, x, y *uint64, n int)
+TEXT ·andNotAVX512(SB), NOSPLIT, $0-32
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+	MOVQ n+24(FP), CX
+	SHRQ $4, CX
+
+andNotLoop:
+	COMBINE512(VPANDNQ)
+	DECQ CX
+	JNZ  andNotLoop
+	VZEROUPPER
+	RET
+
+// func notAVX512(dst, x *uint64, n int)
+TEXT ·notAVX512(SB), NOSPLIT, $0-24
+	MOVQ dst+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ n+16(FP), CX
+	SHRQ $4, CX
+
+	VPTERNLOGQ $0xff, Z15, Z15, Z15
+
+notLoop:
+	VPXORQ    0(SI), Z15, Z0
+	VPXORQ    64(SI), Z15, Z1
+	VMOVDQU64 Z0, 0(DI)
+	VMOVDQU64 Z1, 64(DI)
+	ADDQ      $128, SI
+	ADDQ      $128, DI
+	DECQ      CX
+	JNZ       notLoop
+	VZEROUPPER
+	RET
+
+// func equalAVX512(x, y *uint64, n int) bool
+TEXT ·equalAVX512(SB), NOSPLIT, $0-25
+	MOVQ x+0(FP), SI
+	MOVQ y+8(FP), DX
+	MOVQ n+16(FP), CX
+	SHRQ $4, CX
+
+equalLoop:
+	VMOVDQU64 0(SI), Z0
+	VMOVDQU64 64(SI), Z1
+	VPXORQ    0(DX), Z0, Z0
+	VPXORQ    64(DX), Z1, Z1
+	VPORQ     Z1, Z0, Z0
+	VPTESTMQ  Z0, Z0, K1
+	KORTESTW  K1, K1
+	JNZ       different
+	ADDQ      $128, SI
+	ADDQ      $128, DX
+	DECQ      CX
+	JNZ       equalLoop
+
+	MOVB $1, ret+24(FP)
+	VZEROUPPER
+	RET
+
+different:
+	MOVB $0, ret+24(FP)
+	VZEROUPPER
+	RET
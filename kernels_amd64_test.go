@@ -0,0 +1,118 @@
+//go:build !purego
+// +build !purego
+
+package bitarray
+
+import (
+	"testing"
+)
+
+// amd64Kernels are the assembly kernels for one instruction set.
+type amd64Kernels struct {
+	supported bool
+	and       func(dst, x, y *uint64, n int)
+	or        func(dst, x, y *uint64, n int)
+	xor       func(dst, x, y *uint64, n int)
+	andNot    func(dst, x, y *uint64, n int)
+	not       func(dst, x *uint64, n int)
+	equal     func(x, y *uint64, n int) bool
+}
+
+func TestAMD64Kernels(t *testing.T) {
+	sets := map[string]amd64Kernels{
+		"avx2":   {hasAVX2, andAVX2, orAVX2, xorAVX2, andNotAVX2, notAVX2, equalAVX2},
+		"avx512": {hasAVX512, andAVX512, orAVX512, xorAVX512, andNotAVX512, notAVX512, equalAVX512},
+	}
+
+	for name, kernels := range sets {
+		if !kernels.supported {
+			t.Logf("%v is not supported", name)
+			continue
+		}
+
+		for n := simdWords; n < 50*simdWords; n += simdWords {
+			x, y := randomWords(n), randomWords(n)
+			combines := map[string][2]func(dst, x, y []uint64){
+				"and":    {func(dst, x, y []uint64) { kernels.and(&dst[0], &x[0], &y[0], n) }, andWordsGeneric},
+				"or":     {func(dst, x, y []uint64) { kernels.or(&dst[0], &x[0], &y[0], n) }, orWordsGeneric},
+				"xor":    {func(dst, x, y []uint64) { kernels.xor(&dst[0], &x[0], &y[0], n) }, xorWordsGeneric},
+				"andnot": {func(dst, x, y []uint64) { kernels.andNot(&dst[0], &x[0], &y[0], n) }, andNotWordsGeneric},
+				"not":    {func(dst, x, _ []uint64) { kernels.not(&dst[0], &x[0], n) }, func(dst, x, _ []uint64) { notWordsGeneric(dst, x) }},
+			}
+
+			for op, f := range combines {
+				dst, expected := make([]uint64, n), make([]uint64, n)
+				f[0](dst, x, y)
+				f[1](expected, x, y)
+				if !equalWordsGeneric(dst, expected) {
+					t.Errorf("%v: %v does not match %v", name, op, n)
+				}
+			}
+
+			copy(y, x)
+			if !kernels.equal(&x[0], &y[0], n) {
+				t.Errorf("%v: equal does not match %v", name, n)
+			}
+
+			y[n-1-n%7] ^= 1 << uint(n%64)
+			if kernels.equal(&x[0], &y[0], n) {
+				t.Errorf("%v: difference was not detected %v", name, n)
+			}
+		}
+	}
+}
+
+func TestPopcountAVX2(t *testing.T) {
+	if !hasAVX2 {
+		t.Skip("AVX2 is not supported")
+	}
+
+	for n := simdWords; n < 50*simdWords; n += simdWords {
+		words := randomWords(n)
+		if popcountAVX2(&words[0], n) != popcountGeneric(words) {
+			t.Errorf("popcount does not match %v", n)
+		}
+	}
+}
+
+func TestPopcountAVX512(t *testing.T) {
+	if !hasAVX512POPCNT {
+		t.Skip("AVX-512 VPOPCNTQ is not supported")
+	}
+
+	for n := simdWords; n < 50*simdWords; n += simdWords {
+		words := randomWords(n)
+		if popcountAVX512(&words[0], n) != popcountGeneric(words) {
+			t.Errorf("popcount does not match %v", n)
+		}
+	}
+}
+
+func BenchmarkAMD64Kernels(b *testing.B) {
+	words, dst := randomWords(benchmarkWords), make([]uint64, benchmarkWords)
+	benchmarks := map[string]struct {
+		supported bool
+		f         func()
+	}{
+		"popcount/avx2":   {hasAVX2, func() { popcountAVX2(&words[0], benchmarkWords) }},
+		"popcount/avx512": {hasAVX512POPCNT, func() { popcountAVX512(&words[0], benchmarkWords) }},
+		"and/avx2":        {hasAVX2, func() { andAVX2(&dst[0], &words[0], &words[0], benchmarkWords) }},
+		"and/avx512":      {hasAVX512, func() { andAVX512(&dst[0], &words[0], &words[0], benchmarkWords) }},
+		"equal/avx2":      {hasAVX2, func() { equalAVX2(&dst[0], &dst[0], benchmarkWords) }},
+		"equal/avx512":    {hasAVX512, func() { equalAVX512(&dst[0], &dst[0], benchmarkWords) }},
+	}
+
+	for name, benchmark := range benchmarks {
+		benchmark := benchmark
+		b.Run(name, func(b *testing.B) {
+			if !benchmark.supported {
+				b.Skip("not supported")
+			}
+
+			b.SetBytes(8 * benchmarkWords)
+			for i := 0; i < b.N; i++ {
+				benchmark.f()
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+//go:build !purego
+// +build !purego
+
+package bitarray
+
+// simdWords is the number of words processed by one iteration of the assembly kernels.
+// NEON is always available on arm64.
+const simdWords = 8
+
+//go:noescape
+func popcountNEON(words *uint64, n int) int
+
+//go:noescape
+func andNEON(dst, x, y *uint64, n int)
+
+//go:noescape
+func orNEON(dst, x, y *uint64, n int)
+
+//go:noescape
+func xorNEON(dst, x, y *uint64, n int)
+
+//go:noescape
+func andNotNEON(dst, x, y *uint64, n int)
+
+//go:noescape
+func notNEON(dst, x *uint64, n int)
+
+//go:noescape
+func equalNEON(x, y *uint64, n int) bool
+
+// simdLength returns the number of leading words of n to be processed by the assembly kernels.
+func simdLength(n int) int {
+	return n &^ (simdWords - 1)
+}
+
+func popcount(words []uint64) int {
+	n := simdLength(len(words))
+	count := 0
+	if n > 0 {
+		count = popcountNEON(&words[0], n)
+	}
+
+	return count + popcountGeneric(words[n:])
+}
+
+func andWords(dst, x, y []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 {
+		andNEON(&dst[0], &x[0], &y[0], n)
+	}
+
+	andWordsGeneric(dst[n:], x[n:], y[n:])
+}
+
+func orWords(dst, x, y []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 {
+		orNEON(&dst[0], &x[0], &y[0], n)
+	}
+
+	orWordsGeneric(dst[n:], x[n:], y[n:])
+}
+
+func xorWords(dst, x, y []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 {
+		xorNEON(&dst[0], &x[0], &y[0], n)
+	}
+
+	xorWordsGeneric(dst[n:], x[n:], y[n:])
+}
+
+func andNotWords(dst, x, y []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 {
+		andNotNEON(&dst[0], &x[0], &y[0], n)
+	}
+
+	andNotWordsGeneric(dst[n:], x[n:], y[n:])
+}
+
+func notWords(dst, x []uint64) {
+	n := simdLength(len(dst))
+	if n > 0 {
+		notNEON(&dst[0], &x[0], n)
+	}
+
+	notWordsGeneric(dst[n:], x[n:])
+}
+
+func equalWords(x, y []uint64) bool {
+	n := simdLength(len(x))
+	if n > 0 && !equalNEON(&x[0], &y[0], n) {
+		return false
+	}
+
+	return equalWordsGeneric(x[n:], y[n:])
+}
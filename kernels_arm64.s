@@ -0,0 +1,167 @@
+// +build !purego
+
+#include "textflag.h"
+
+// func popcountNEON(words *uint64, n int) int
+TEXT ·popcountNEON(SB), NOSPLIT, $0-24
+	MOVD words+0(FP), R0
+	MOVD n+8(FP), R1
+	LSR  $3, R1, R1
+	MOVD $0, R2
+
+popcountLoop:
+	VLD1.P  64(R0), [V0.B16, V1.B16, V2.B16, V3.B16]
+	VCNT    V0.B16, V0.B16
+	VCNT    V1.B16, V1.B16
+	VCNT    V2.B16, V2.B16
+	VCNT    V3.B16, V3.B16
+	VADD    V1.B16, V0.B16, V0.B16
+	VADD    V3.B16, V2.B16, V2.B16
+	VADD    V2.B16, V0.B16, V0.B16
+	VUADDLV V0.B16, V4
+	VMOV    V4.D[0], R3
+	ADD     R3, R2, R2
+	SUBS    $1, R1, R1
+	BNE     popcountLoop
+
+	MOVD R2, ret+16(FP)
+	RET
+
+// func andNEON(dst, x, y *uint64, n int)
+TEXT ·andNEON(SB), NOSPLIT, $0-32
+	MOVD dst+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD n+24(FP), R3
+	LSR  $3, R3, R3
+
+andLoop:
+	VLD1.P 64(R1), [V0.B16, V1.B16, V2.B16, V3.B16]
+	VLD1.P 64(R2), [V4.B16, V5.B16, V6.B16, V7.B16]
+	VAND   V4.B16, V0.B16, V0.B16
+	VAND   V5.B16, V1.B16, V1.B16
+	VAND   V6.B16, V2.B16, V2.B16
+	VAND   V7.B16, V3.B16, V3.B16
+	VST1.P [V0.B16, V1.B16, V2.B16, V3.B16], 64(R0)
+	SUBS   $1, R3, R3
+	BNE    andLoop
+	RET
+
+// func orNEON(dst, x, y *uint64, n int)
+TEXT ·orNEON(SB), NOSPLIT, $0-32
+	MOVD dst+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD n+24(FP), R3
+	LSR  $3, R3, R3
+
+orLoop:
+	VLD1.P 64(R1), [V0.B16, V1.B16, V2.B16, V3.B16]
+	VLD1.P 64(R2), [V4.B16, V5.B16, V6.B16, V7.B16]
+	VORR   V4.B16, V0.B16, V0.B16
+	VORR   V5.B16, V1.B16, V1.B16
+	VORR   V6.B16, V2.B16, V2.B16
+	VORR   V7.B16, V3.B16, V3.B16
+	VST1.P [V0.B16, V1.B16, V2.B16, V3.B16], 64(R0)
+	SUBS   $1, R3, R3
+	BNE    orLoop
+	RET
+
+// func xorNEON(dst, x, y *uint64, n int)
+TEXT ·xorNEON(SB), NOSPLIT, $0-32
+	MOVD dst+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD n+24(FP), R3
+	LSR  $3, R3, R3
+
+xorLoop:
+	VLD1.P 64(R1), [V0.B16, V1.B16, V2.B16, V3.B16]
+	VLD1.P 64(R2), [V4.B16, V5.B16, V6.B16, V7.B16]
+	VEOR   V4.B16, V0.B16, V0.B16
+	VEOR   V5.B16, V1.B16, V1.B16
+	VEOR   V6.B16, V2.B16, V2.B16
+	VEOR   V7.B16, V3.B16, V3.B16
+	VST1.P [V0.B16, V1.B16, V2.B16, V3.B16], 64(R0)
+	SUBS   $1, R3, R3
+	BNE    xorLoop
+	RET
+
+// func andNotNEON(dst, x, y *uint64, n int)
+TEXT ·andNotNEON(SB), NOSPLIT, $0-32
+	MOVD dst+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD y+16(FP), R2
+	MOVD n+24(FP), R3
+	LSR  $3, R3, R3
+
+	// V15 is all ones, so that y can be inverted with VEOR.
+	VCMEQ V15.D2, V15.D2, V15.D2
+
+andNotLoop:
+	VLD1.P 64(R1), [V0.B16, V1.B16, V2.B16, V3.B16]
+	VLD1.P 64(R2), [V4.B16, V5.B16, V6.B16, V7.B16]
+	VEOR   V15.B16, V4.B16, V4.B16
+	VEOR   V15.B16, V5.B16, V5.B16
+	VEOR   V15.B16, V6.B16, V6.B16
+	VEOR   V15.B16, V7.B16, V7.B16
+	VAND   V4.B16, V0.B16, V0.B16
+	VAND   V5.B16, V1.B16, V1.B16
+	VAND   V6.B16, V2.B16, V2.B16
+	VAND   V7.B16, V3.B16, V3.B16
+	VST1.P [V0.B16, V1.B16, V2.B16, V3.B16], 64(R0)
+	SUBS   $1, R3, R3
+	BNE    andNotLoop
+	RET
+
+// func notNEON(dst, x *uint64, n int)
+TEXT ·notNEON(SB), NOSPLIT, $0-24
+	MOVD dst+0(FP), R0
+	MOVD x+8(FP), R1
+	MOVD n+16(FP), R3
+	LSR  $3, R3, R3
+
+	VCMEQ V15.D2, V15.D2, V15.D2
+
+notLoop:
+	VLD1.P 64(R1), [V0.B16, V1.B16, V2.B16, V3.B16]
+	VEOR   V15.B16, V0.B16, V0.B16
+	VEOR   V15.B16, V1.B16, V1.B16
+	VEOR   V15.B16, V2.B16, V2.B16
+	VEOR   V15.B16, V3.B16, V3.B16
+	VST1.P [V0.B16, V1.B16, V2.B16, V3.B16], 64(R0)
+	SUBS   $1, R3, R3
+	BNE    notLoop
+	RET
+
+// func equalNEON(x, y *uint64, n int) bool
+TEXT ·equalNEON(SB), NOSPLIT, $0-25
+	MOVD x+0(FP), R1
+	MOVD y+8(FP), R2
+	MOVD n+16(FP), R3
+	LSR  $3, R3, R3
+
+equalLoop:
+	VLD1.P 64(R1), [V0.B16, V1.B16, V2.B16, V3.B16]
+	VLD1.P 64(R2), [V4.B16, V5.B16, V6.B16, V7.B16]
+	VEOR   V4.B16, V0.B16, V0.B16
+	VEOR   V5.B16, V1.B16, V1.B16
+	VEOR   V6.B16, V2.B16, V2.B16
+	VEOR   V7.B16, V3.B16, V3.B16
+	VORR   V1.B16, V0.B16, V0.B16
+	VORR   V3.B16, V2.B16, V2.B16
+	VORR   V2.B16, V0.B16, V0.B16
+	VMOV   V0.D[0], R4
+	VMOV   V0.D[1], R5
+	ORR    R4, R5, R5
+	CBNZ   R5, different
+	SUBS   $1, R3, R3
+	BNE    equalLoop
+
+	MOVD $1, R6
+	MOVB R6, ret+24(FP)
+	RET
+
+different:
+	MOVB ZR, ret+24(FP)
+	RET
@@ -0,0 +1,32 @@
+//go:build (!amd64 && !arm64) || purego
+// +build !amd64,!arm64 purego
+
+package bitarray
+
+func popcount(words []uint64) int {
+	return popcountGeneric(words)
+}
+
+func andWords(dst, x, y []uint64) {
+	andWordsGeneric(dst, x, y)
+}
+
+func orWords(dst, x, y []uint64) {
+	orWordsGeneric(dst, x, y)
+}
+
+func xorWords(dst, x, y []uint64) {
+	xorWordsGeneric(dst, x, y)
+}
+
+func andNotWords(dst, x, y []uint64) {
+	andNotWordsGeneric(dst, x, y)
+}
+
+func notWords(dst, x []uint64) {
+	notWordsGeneric(dst, x)
+}
+
+func equalWords(x, y []uint64) bool {
+	return equalWordsGeneric(x, y)
+}
@@ -0,0 +1,166 @@
+package bitarray
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomWords(n int) []uint64 {
+	words := make([]uint64, n)
+	for i := range words {
+		words[i] = rand.Uint64()
+	}
+
+	return words
+}
+
+func TestKernels(t *testing.T) {
+	combines := map[string][2]func(dst, x, y []uint64){
+		"and":    {andWords, andWordsGeneric},
+		"or":     {orWords, orWordsGeneric},
+		"xor":    {xorWords, xorWordsGeneric},
+		"andnot": {andNotWords, andNotWordsGeneric},
+	}
+
+	for n := 0; n < 200; n++ {
+		// The offset makes the slices start at different alignments.
+		offset := n % 5
+		x := randomWords(n + offset)[offset:]
+		y := randomWords(n)
+		if n%3 == 0 {
+			copy(y, x)
+		}
+
+		if popcount(x) != popcountGeneric(x) {
+			t.Errorf("popcount does not match %v %v %v", n, popcount(x), popcountGeneric(x))
+		}
+
+		if equalWords(x, y) != equalWordsGeneric(x, y) {
+			t.Errorf("equal does not match %v", n)
+		}
+
+		if n > 0 {
+			y[n-1] ^= 1
+			if equalWords(x, y) {
+				t.Errorf("last word was not compared %v", n)
+			}
+		}
+
+		for name, kernels := range combines {
+			dst := make([]uint64, n)
+			expected := make([]uint64, n)
+			kernels[0](dst, x, y)
+			kernels[1](expected, x, y)
+			if !equalWordsGeneric(dst, expected) {
+				t.Errorf("%v: value does not match %v", name, n)
+			}
+
+			aliased := append([]uint64{}, x...)
+			kernels[0](aliased, aliased, y)
+			if !equalWordsGeneric(aliased, expected) {
+				t.Errorf("%v: aliased value does not match %v", name, n)
+			}
+		}
+
+		dst := make([]uint64, n)
+		expected := make([]uint64, n)
+		notWords(dst, x)
+		notWordsGeneric(expected, x)
+		if !equalWordsGeneric(dst, expected) {
+			t.Errorf("not: value does not match %v", n)
+		}
+	}
+}
+
+func TestCombine(t *testing.T) {
+	ops := map[combineOp]func(u, v uint64) uint64{
+		opAnd:    func(u, v uint64) uint64 { return u & v },
+		opOr:     func(u, v uint64) uint64 { return u | v },
+		opXor:    func(u, v uint64) uint64 { return u ^ v },
+		opAndNot: func(u, v uint64) uint64 { return u &^ v },
+	}
+
+	for op, f := range ops {
+		for _, sizes := range [][3]int{{40, 40, 40}, {40, 20, 40}, {40, 40, 20}, {40, 20, 30}, {20, 40, 30}, {0, 10, 10}} {
+			xs, ys := randomWords(sizes[1]), randomWords(sizes[2])
+			dst := randomWords(sizes[0])
+			combine(op, dst, xs, ys)
+			for i := range dst {
+				if dst[i] != f(word(xs, i), word(ys, i)) {
+					t.Errorf("value does not match %v %v %v", op, sizes, i)
+				}
+			}
+		}
+	}
+}
+
+const benchmarkWords = 1 << 16
+
+func BenchmarkPopcount(b *testing.B) {
+	words := randomWords(benchmarkWords)
+	kernels := map[string]func([]uint64) int{
+		"generic": popcountGeneric,
+		"simd":    popcount,
+	}
+
+	for name, kernel := range kernels {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(8 * benchmarkWords)
+			for i := 0; i < b.N; i++ {
+				kernel(words)
+			}
+		})
+	}
+}
+
+func BenchmarkAndWords(b *testing.B) {
+	dst, x, y := make([]uint64, benchmarkWords), randomWords(benchmarkWords), randomWords(benchmarkWords)
+	kernels := map[string]func(dst, x, y []uint64){
+		"generic": andWordsGeneric,
+		"simd":    andWords,
+	}
+
+	for name, kernel := range kernels {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(8 * benchmarkWords)
+			for i := 0; i < b.N; i++ {
+				kernel(dst, x, y)
+			}
+		})
+	}
+}
+
+func BenchmarkNotWords(b *testing.B) {
+	dst, x := make([]uint64, benchmarkWords), randomWords(benchmarkWords)
+	kernels := map[string]func(dst, x []uint64){
+		"generic": notWordsGeneric,
+		"simd":    notWords,
+	}
+
+	for name, kernel := range kernels {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(8 * benchmarkWords)
+			for i := 0; i < b.N; i++ {
+				kernel(dst, x)
+			}
+		})
+	}
+}
+
+func BenchmarkEqualWords(b *testing.B) {
+	x := randomWords(benchmarkWords)
+	y := append([]uint64{}, x...)
+	kernels := map[string]func(x, y []uint64) bool{
+		"generic": equalWordsGeneric,
+		"simd":    equalWords,
+	}
+
+	for name, kernel := range kernels {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(8 * benchmarkWords)
+			for i := 0; i < b.N; i++ {
+				kernel(x, y)
+			}
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package bitarray
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Format is a text and JSON representation of a BitArray, see MarshalTextFormat and MarshalJSONFormat.
+type Format int
+
+const (
+	// FormatBits represents the BitArray as a string of '0' and '1', starting at index 0.
+	FormatBits Format = iota
+	// FormatBase64 represents the BitArray as the base64 of its binary encoding.
+	FormatBase64
+	// FormatIndices represents the BitArray as its length and the indices of its set bits.
+	// Lengths above 2^28 are rejected when decoding, use FormatBase64 for larger BitArrays.
+	FormatIndices
+)
+
+// binaryVersion is the version of the format written by MarshalBinary.
+const binaryVersion = 1
+
+// binaryHeaderSize is the size of the version byte and the length.
+const binaryHeaderSize = 1 + 8
+
+const maxInt = int(^uint(0) >> 1)
+
+// maxIndicesLength is the maximum length accepted when decoding FormatIndices.
+// Unlike the other formats, the length of FormatIndices is not backed by the
+// size of the data, so it is bounded to keep untrusted input from causing a
+// huge allocation.
+const maxIndicesLength = 1 << 28
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// The encoding is a version byte, the length as a little-endian uint64 and
+// the bits packed into ceil(length/8) bytes, least significant bit first.
+func (b *BitArray) MarshalBinary() ([]byte, error) {
+	data := make([]byte, binaryHeaderSize, binaryHeaderSize+byteSize(b.length))
+	data[0] = binaryVersion
+	binary.LittleEndian.PutUint64(data[1:], uint64(b.length))
+	return b.appendBytes(data), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (b *BitArray) UnmarshalBinary(data []byte) error {
+	if len(data) < binaryHeaderSize {
+		return errors.New("binary data too short")
+	}
+
+	if data[0] != binaryVersion {
+		return errors.New("unsupported binary version")
+	}
+
+	length, err := decodeLength(data[1:binaryHeaderSize])
+	if err != nil {
+		return err
+	}
+
+	data = data[binaryHeaderSize:]
+	if len(data) != byteSize(length) {
+		return errors.New("binary data size does not match length")
+	}
+
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		return err
+	}
+
+	if err := bitArray.setBytes(data); err != nil {
+		return err
+	}
+
+	b.blocks = bitArray.blocks
+	b.length = bitArray.length
+	return nil
+}
+
+// decodeLength decodes a little-endian uint64 length.
+func decodeLength(data []byte) (int, error) {
+	length := binary.LittleEndian.Uint64(data)
+	if length > uint64(maxInt) {
+		return 0, errors.New("length overflows int")
+	}
+
+	return int(length), nil
+}
+
+// byteSize returns the number of bytes needed to hold length bits.
+func byteSize(length int) int {
+	return length/8 + (length%8+7)/8
+}
+
+// appendBytes appends the bits packed into bytes, least significant bit first.
+func (b *BitArray) appendBytes(data []byte) []byte {
+	var buf [8]byte
+	size := byteSize(b.length)
+	for i := 0; size > 0; i++ {
+		binary.LittleEndian.PutUint64(buf[:], b.blocks[i])
+		n := len(buf)
+		if n > size {
+			n = size
+		}
+
+		data = append(data, buf[:n]...)
+		size -= n
+	}
+
+	return data
+}
+
+// setBytes sets the blocks from bits packed into bytes, least significant bit first.
+// It fails if any bit beyond the length is set.
+func (b *BitArray) setBytes(data []byte) error {
+	var buf [8]byte
+	for i := range b.blocks {
+		n := copy(buf[:], data[i*8:])
+		for j := n; j < len(buf); j++ {
+			buf[j] = 0
+		}
+
+		b.blocks[i] = binary.LittleEndian.Uint64(buf[:])
+	}
+
+	mod := b.length % bitPerBlock
+	if mod != 0 && b.blocks[len(b.blocks)-1]>>uint64(mod) != 0 {
+		return errors.New("bits set beyond length")
+	}
+
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The BitArray is represented in FormatBits, see MarshalTextFormat for the others.
+func (b *BitArray) MarshalText() ([]byte, error) {
+	return b.MarshalTextFormat(FormatBits)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It accepts FormatBits, see UnmarshalTextFormat for the others.
+func (b *BitArray) UnmarshalText(text []byte) error {
+	return b.UnmarshalTextFormat(FormatBits, text)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The BitArray is represented in FormatBits, see MarshalJSONFormat for the others.
+func (b *BitArray) MarshalJSON() ([]byte, error) {
+	return b.MarshalJSONFormat(FormatBits)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts FormatBits, see UnmarshalJSONFormat for the others.
+func (b *BitArray) UnmarshalJSON(data []byte) error {
+	return b.UnmarshalJSONFormat(FormatBits, data)
+}
+
+// MarshalTextFormat returns the text representation of the BitArray in the format.
+//
+// FormatIndices is encoded as the length, a colon and the comma separated
+// indices of set bits, for example "10:1,3,7".
+func (b *BitArray) MarshalTextFormat(format Format) ([]byte, error) {
+	switch format {
+	case FormatBits:
+		return []byte(b.String()), nil
+	case FormatBase64:
+		data, err := b.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		text := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+		base64.StdEncoding.Encode(text, data)
+		return text, nil
+	case FormatIndices:
+		text := strconv.AppendInt(nil, int64(b.length), 10)
+		text = append(text, ':')
+		for i, index := range b.indices() {
+			if i > 0 {
+				text = append(text, ',')
+			}
+
+			text = strconv.AppendInt(text, int64(index), 10)
+		}
+
+		return text, nil
+	}
+
+	return nil, errors.New("unknown format")
+}
+
+// UnmarshalTextFormat sets the BitArray from its text representation in the format.
+func (b *BitArray) UnmarshalTextFormat(format Format, text []byte) error {
+	switch format {
+	case FormatBits:
+		bitArray, err := FromString(string(text))
+		if err != nil {
+			return err
+		}
+
+		b.blocks = bitArray.blocks
+		b.length = bitArray.length
+		return nil
+	case FormatBase64:
+		data := make([]byte, base64.StdEncoding.DecodedLen(len(text)))
+		n, err := base64.StdEncoding.Strict().Decode(data, text)
+		if err != nil {
+			return err
+		}
+
+		return b.UnmarshalBinary(data[:n])
+	case FormatIndices:
+		s := string(text)
+		sep := strings.IndexByte(s, ':')
+		if sep < 0 {
+			return errors.New("missing length in indices")
+		}
+
+		length, err := parseIndex(s[:sep])
+		if err != nil {
+			return err
+		}
+
+		var indices []int
+		if s[sep+1:] != "" {
+			for _, field := range strings.Split(s[sep+1:], ",") {
+				index, err := parseIndex(field)
+				if err != nil {
+					return err
+				}
+
+				indices = append(indices, index)
+			}
+		}
+
+		return b.setIndices(length, indices)
+	}
+
+	return errors.New("unknown format")
+}
+
+// indicesObject is the JSON representation of FormatIndices.
+type indicesObject struct {
+	Length  *int  `json:"length"`
+	Indices []int `json:"indices"`
+}
+
+// MarshalJSONFormat returns the JSON representation of the BitArray in the format.
+//
+// FormatIndices is encoded as an object such as {"length":10,"indices":[1,3,7]},
+// the other formats as a string holding their text representation.
+func (b *BitArray) MarshalJSONFormat(format Format) ([]byte, error) {
+	if format == FormatIndices {
+		indices := b.indices()
+		if indices == nil {
+			indices = []int{}
+		}
+
+		return json.Marshal(indicesObject{Length: &b.length, Indices: indices})
+	}
+
+	text, err := b.MarshalTextFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSONFormat sets the BitArray from its JSON representation in the format.
+// JSON null leaves the BitArray unchanged.
+func (b *BitArray) UnmarshalJSONFormat(format Format, data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if format == FormatIndices {
+		var v indicesObject
+		decoder := json.NewDecoder(strings.NewReader(string(data)))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&v); err != nil {
+			return err
+		}
+
+		if _, err := decoder.Token(); err != io.EOF {
+			return errors.New("trailing data after indices")
+		}
+
+		if v.Length == nil || v.Indices == nil {
+			return errors.New("missing length or indices")
+		}
+
+		return b.setIndices(*v.Length, v.Indices)
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	return b.UnmarshalTextFormat(format, []byte(text))
+}
+
+// Base64JSON wraps a BitArray to be marshaled to text and JSON in FormatBase64,
+// for example as a field of a struct. A nil BitArray is allocated when unmarshaling.
+type Base64JSON struct {
+	*BitArray
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (v Base64JSON) MarshalText() ([]byte, error) {
+	if v.BitArray == nil {
+		return nil, errors.New("nil BitArray")
+	}
+
+	return v.BitArray.MarshalTextFormat(FormatBase64)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (v *Base64JSON) UnmarshalText(text []byte) error {
+	if v.BitArray == nil {
+		v.BitArray = &BitArray{}
+	}
+
+	return v.BitArray.UnmarshalTextFormat(FormatBase64, text)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v Base64JSON) MarshalJSON() ([]byte, error) {
+	if v.BitArray == nil {
+		return []byte("null"), nil
+	}
+
+	return v.BitArray.MarshalJSONFormat(FormatBase64)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *Base64JSON) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if v.BitArray == nil {
+		v.BitArray = &BitArray{}
+	}
+
+	return v.BitArray.UnmarshalJSONFormat(FormatBase64, data)
+}
+
+// IndicesJSON wraps a BitArray to be marshaled to text and JSON in FormatIndices,
+// for example as a field of a struct. A nil BitArray is allocated when unmarshaling.
+type IndicesJSON struct {
+	*BitArray
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (v IndicesJSON) MarshalText() ([]byte, error) {
+	if v.BitArray == nil {
+		return nil, errors.New("nil BitArray")
+	}
+
+	return v.BitArray.MarshalTextFormat(FormatIndices)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (v *IndicesJSON) UnmarshalText(text []byte) error {
+	if v.BitArray == nil {
+		v.BitArray = &BitArray{}
+	}
+
+	return v.BitArray.UnmarshalTextFormat(FormatIndices, text)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v IndicesJSON) MarshalJSON() ([]byte, error) {
+	if v.BitArray == nil {
+		return []byte("null"), nil
+	}
+
+	return v.BitArray.MarshalJSONFormat(FormatIndices)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *IndicesJSON) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if v.BitArray == nil {
+		v.BitArray = &BitArray{}
+	}
+
+	return v.BitArray.UnmarshalJSONFormat(FormatIndices, data)
+}
+
+// indices returns the indices of set bits in ascending order.
+func (b *BitArray) indices() []int {
+	var indices []int
+	for i, v := range b.blocks {
+		for v != 0 {
+			indices = append(indices, i*bitPerBlock+bits.TrailingZeros64(v))
+			v &= v - 1
+		}
+	}
+
+	return indices
+}
+
+// setIndices replaces the BitArray by one of the given length with the given
+// bits set. The length must not exceed maxIndicesLength and the indices must
+// be strictly increasing and within the length.
+func (b *BitArray) setIndices(length int, indices []int) error {
+	if length < 0 || length > maxIndicesLength {
+		return errors.New("length out of range")
+	}
+
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		return err
+	}
+
+	prev := -1
+	for _, index := range indices {
+		if index <= prev {
+			return errors.New("indices not strictly increasing")
+		}
+
+		if err := bitArray.Set(index); err != nil {
+			return err
+		}
+
+		prev = index
+	}
+
+	b.blocks = bitArray.blocks
+	b.length = bitArray.length
+	return nil
+}
+
+// parseIndex parses a non-negative decimal integer without sign.
+func parseIndex(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("empty index")
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, errors.New("invalid character in index")
+		}
+	}
+
+	return strconv.Atoi(s)
+}
@@ -0,0 +1,301 @@
+package bitarray
+
+import (
+	"encoding"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestBitArray_MarshalBinary(t *testing.T) {
+	for length := 0; length < 1000; length++ {
+		bitArray, err := NewBitArray(length)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < bitArray.length; i += 7 {
+			if err := bitArray.Set(i); err != nil {
+				t.Error(err)
+			}
+		}
+
+		if length > 0 {
+			if err := bitArray.Set(length - 1); err != nil {
+				t.Error(err)
+			}
+		}
+
+		data, err := bitArray.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(data) != binaryHeaderSize+(length+7)/8 {
+			t.Errorf("size does not match %v %v", length, len(data))
+		}
+
+		unmarshaled := &BitArray{}
+		if err := unmarshaled.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+
+		if unmarshaled.length != length {
+			t.Errorf("length does not match %v %v", length, unmarshaled.length)
+		}
+
+		for i := 0; i < length; i++ {
+			x, err := bitArray.Get(i)
+			if err != nil {
+				t.Error(err)
+			}
+
+			y, err := unmarshaled.Get(i)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if x != y {
+				t.Errorf("value does not match %v %v %v %v", length, i, x, y)
+			}
+		}
+	}
+}
+
+func TestBitArray_UnmarshalBinary(t *testing.T) {
+	bitArray, err := NewBitArray(70)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bitArray.Set(69); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := bitArray.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := map[string][]byte{
+		"empty":     {},
+		"header":    data[:binaryHeaderSize-1],
+		"truncated": data[:len(data)-1],
+		"trailing":  append(append([]byte{}, data...), 0),
+		"version":   append([]byte{binaryVersion + 1}, data[1:]...),
+		"stray":     append(append([]byte{}, data[:len(data)-1]...), data[len(data)-1]|0x80),
+		"overflow":  {binaryVersion, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	}
+
+	for name, data := range invalid {
+		unmarshaled := &BitArray{}
+		if err := unmarshaled.UnmarshalBinary(data); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
+
+func TestBitArray_MarshalJSON(t *testing.T) {
+	for _, format := range []Format{FormatBits, FormatBase64, FormatIndices} {
+		for length := 0; length < 300; length++ {
+			bitArray, err := NewBitArray(length)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < bitArray.length; i += 7 {
+				if err := bitArray.Set(i); err != nil {
+					t.Error(err)
+				}
+			}
+
+			data, err := bitArray.MarshalJSONFormat(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !json.Valid(data) {
+				t.Fatalf("invalid JSON %v %v %s", format, length, data)
+			}
+
+			unmarshaled := &BitArray{}
+			if err := unmarshaled.UnmarshalJSONFormat(format, data); err != nil {
+				t.Fatalf("%v %v %s", format, length, data)
+			}
+
+			if unmarshaled.length != length {
+				t.Errorf("length does not match %v %v %v", format, length, unmarshaled.length)
+			}
+
+			for i := 0; i < length; i++ {
+				x, err := bitArray.Get(i)
+				if err != nil {
+					t.Error(err)
+				}
+
+				y, err := unmarshaled.Get(i)
+				if err != nil {
+					t.Error(err)
+				}
+
+				if x != y {
+					t.Errorf("value does not match %v %v %v %v %v", format, length, i, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestBitArray_MarshalText(t *testing.T) {
+	bitArray, err := NewBitArray(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []int{1, 3, 7} {
+		if err := bitArray.Set(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[Format]string{
+		FormatBits:    "0101000100",
+		FormatBase64:  "AQoAAAAAAAAAigA=",
+		FormatIndices: "10:1,3,7",
+	}
+
+	for format, s := range expected {
+		text, err := bitArray.MarshalTextFormat(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(text) != s {
+			t.Errorf("value does not match %v %v %v", format, string(text), s)
+		}
+	}
+
+	data, err := json.Marshal(IndicesJSON{bitArray})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"length":10,"indices":[1,3,7]}` {
+		t.Errorf("value does not match %s", data)
+	}
+}
+
+func TestBitArray_UnmarshalText(t *testing.T) {
+	invalid := map[Format][]string{
+		FormatBits:    {"0102", "01 1", "true"},
+		FormatBase64:  {"!", "AQoAAAAAAAAAigA", "AQoAAAAAAAAAigQ="},
+		FormatIndices: {"", "10", "10:1,", "10:,1", "10:3,1", "10:1,1", "10:10", "10:-1", "10:+1", "-1:", "x:", "9223372036854775807:", "268435457:"},
+	}
+
+	for format, texts := range invalid {
+		for _, text := range texts {
+			bitArray := &BitArray{}
+			if err := bitArray.UnmarshalTextFormat(format, []byte(text)); err == nil {
+				t.Errorf("expected error %v %q", format, text)
+			}
+		}
+	}
+
+	invalidJSON := map[Format][]string{
+		FormatBits:    {`1`, `"012"`, `["0"]`},
+		FormatIndices: {`"10:1"`, `{"length":10}`, `{"indices":[1]}`, `{"length":10,"indices":[1],"x":1}`, `{"length":10,"indices":[1.5]}`, `{"length":10,"indices":[11]}`, `{"length":9223372036854775807,"indices":[]}`, `{"length":-1,"indices":[]}`, `{"length":3,"indices":[1]} trailing`, `{"length":3,"indices":[1]}{}`, `{"length":3,"indices":[1]} 1`},
+	}
+
+	for format, texts := range invalidJSON {
+		for _, text := range texts {
+			bitArray := &BitArray{}
+			if err := bitArray.UnmarshalJSONFormat(format, []byte(text)); err == nil {
+				t.Errorf("expected error %v %q", format, text)
+			}
+		}
+	}
+}
+
+func TestBitArray_UnmarshalTextMaxLength(t *testing.T) {
+	bitArray := &BitArray{}
+	text := strconv.Itoa(maxIndicesLength) + ":" + strconv.Itoa(maxIndicesLength-1)
+	if err := bitArray.UnmarshalTextFormat(FormatIndices, []byte(text)); err != nil {
+		t.Fatal(err)
+	}
+
+	if bitArray.Length() != maxIndicesLength || bitArray.OnesCount() != 1 {
+		t.Errorf("value does not match %v %v", bitArray.Length(), bitArray.OnesCount())
+	}
+}
+
+func TestIndicesJSON(t *testing.T) {
+	type message struct {
+		Bits    *BitArray
+		Base64  Base64JSON
+		Indices IndicesJSON
+		Missing IndicesJSON
+	}
+
+	bitArray := newPatternBitArray(t, 100, 7)
+	slice, err := bitArray.Slice(0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(message{Bits: bitArray, Base64: Base64JSON{slice}, Indices: IndicesJSON{bitArray}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded message
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("%v %s", err, data)
+	}
+
+	for name, v := range map[string]*BitArray{"bits": decoded.Bits, "base64": decoded.Base64.BitArray, "indices": decoded.Indices.BitArray} {
+		if v == nil || !v.Equal(bitArray) {
+			t.Errorf("%v: value does not match %s", name, data)
+		}
+	}
+
+	if decoded.Missing.BitArray != nil {
+		t.Errorf("null was decoded %s", data)
+	}
+
+	clone, err := bitArray.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []*BitArray{clone, slice} {
+		text, err := v.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(text) != bitArray.String() {
+			t.Errorf("equal BitArrays marshal differently %s", text)
+		}
+	}
+}
+
+func TestIndicesJSON_Nil(t *testing.T) {
+	for _, v := range []encoding.TextMarshaler{Base64JSON{}, IndicesJSON{}} {
+		if _, err := v.MarshalText(); err == nil {
+			t.Errorf("expected error %T", v)
+		}
+	}
+
+	if _, err := json.Marshal(map[Base64JSON]int{{}: 1}); err == nil {
+		t.Error("expected error")
+	}
+
+	data, err := json.Marshal([]interface{}{Base64JSON{}, IndicesJSON{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "[null,null]" {
+		t.Errorf("value does not match %s", data)
+	}
+}
@@ -0,0 +1,163 @@
+package bitarray
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// operandBlocks returns the blocks of the BitArrays and the length of the longest one.
+func operandBlocks(arrays []*BitArray) ([][]uint64, int, error) {
+	if len(arrays) == 0 {
+		return nil, 0, errors.New("no BitArrays")
+	}
+
+	blocks := make([][]uint64, len(arrays))
+	length := 0
+	for i, a := range arrays {
+		blocks[i] = a.used()
+		length = maxLength(length, a.length)
+	}
+
+	return blocks, length, nil
+}
+
+// AndAll is the logical AND of all the BitArrays.
+// The result has the length of the longest one and the shorter ones are padded with zeros.
+func AndAll(arrays ...*BitArray) (*BitArray, error) {
+	blocks, length, err := operandBlocks(arrays)
+	if err != nil {
+		return nil, err
+	}
+
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range bitArray.blocks {
+		u := uint64(max)
+		for _, words := range blocks {
+			u &= word(words, i)
+			if u == 0 {
+				break
+			}
+		}
+
+		bitArray.blocks[i] = u
+	}
+
+	return bitArray, nil
+}
+
+// OrAll is the logical OR of all the BitArrays.
+// The result has the length of the longest one and the shorter ones are padded with zeros.
+func OrAll(arrays ...*BitArray) (*BitArray, error) {
+	blocks, length, err := operandBlocks(arrays)
+	if err != nil {
+		return nil, err
+	}
+
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range bitArray.blocks {
+		u := uint64(0)
+		for _, words := range blocks {
+			u |= word(words, i)
+		}
+
+		bitArray.blocks[i] = u
+	}
+
+	return bitArray, nil
+}
+
+// XorAll is the exclusive OR of all the BitArrays.
+// The result has the length of the longest one and the shorter ones are padded with zeros.
+func XorAll(arrays ...*BitArray) (*BitArray, error) {
+	blocks, length, err := operandBlocks(arrays)
+	if err != nil {
+		return nil, err
+	}
+
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range bitArray.blocks {
+		u := uint64(0)
+		for _, words := range blocks {
+			u ^= word(words, i)
+		}
+
+		bitArray.blocks[i] = u
+	}
+
+	return bitArray, nil
+}
+
+// AtLeast returns a BitArray in which a bit is set if it is set in at least k of the BitArrays.
+// The result has the length of the longest one and the shorter ones are padded with zeros.
+func AtLeast(k int, arrays ...*BitArray) (*BitArray, error) {
+	blocks, length, err := operandBlocks(arrays)
+	if err != nil {
+		return nil, err
+	}
+
+	bitArray, err := NewBitArray(length)
+	if err != nil {
+		return nil, err
+	}
+
+	if k <= 0 {
+		if err := bitArray.SetRange(0, length); err != nil {
+			return nil, err
+		}
+
+		return bitArray, nil
+	}
+
+	if k > len(arrays) {
+		return bitArray, nil
+	}
+
+	// The count of every bit position in a block is kept in bit-sliced
+	// counters, where counters[j] holds bit j of the 64 counts.
+	counters := make([]uint64, bits.Len(uint(len(arrays))))
+	for i := range bitArray.blocks {
+		for j := range counters {
+			counters[j] = 0
+		}
+
+		for _, words := range blocks {
+			carry := word(words, i)
+			for j := 0; carry != 0; j++ {
+				counters[j], carry = counters[j]^carry, counters[j]&carry
+			}
+		}
+
+		// Compare the counts with k from the most significant bit down.
+		greater, equal := uint64(0), uint64(max)
+		for j := len(counters) - 1; j >= 0; j-- {
+			if k>>uint(j)&1 != 0 {
+				equal &= counters[j]
+			} else {
+				greater |= equal & counters[j]
+				equal &^= counters[j]
+			}
+		}
+
+		bitArray.blocks[i] = greater | equal
+	}
+
+	return bitArray, nil
+}
+
+// Majority returns a BitArray in which a bit is set if it is set in more than half of the BitArrays.
+// The result has the length of the longest one and the shorter ones are padded with zeros.
+func Majority(arrays ...*BitArray) (*BitArray, error) {
+	return AtLeast(len(arrays)/2+1, arrays...)
+}
@@ -0,0 +1,153 @@
+package bitarray
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newRandomOperands(t *testing.T, n int) ([]*BitArray, [][]bool, int) {
+	arrays := make([]*BitArray, n)
+	values := make([][]bool, n)
+	length := 0
+	for i := range arrays {
+		arrays[i] = newRandomBitArray(t, rand.Intn(300))
+		values[i] = arrays[i].Bools()
+		length = maxLength(length, arrays[i].length)
+	}
+
+	return arrays, values, length
+}
+
+func TestAndAll(t *testing.T) {
+	funcs := map[string]func(arrays ...*BitArray) (*BitArray, error){
+		"and": AndAll,
+		"or":  OrAll,
+		"xor": XorAll,
+	}
+
+	for n := 1; n < 20; n++ {
+		arrays, values, length := newRandomOperands(t, n)
+		for name, f := range funcs {
+			z, err := f(arrays...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := make([]bool, length)
+			for i := range expected {
+				and, or, xor := true, false, false
+				for _, v := range values {
+					and = and && bitAt(v, i)
+					or = or || bitAt(v, i)
+					xor = xor != bitAt(v, i)
+				}
+
+				expected[i] = map[string]bool{"and": and, "or": or, "xor": xor}[name]
+			}
+
+			checkBools(t, name, z, expected)
+		}
+	}
+
+	for name, f := range funcs {
+		if _, err := f(); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	for n := 1; n < 20; n++ {
+		arrays, values, length := newRandomOperands(t, n)
+		counts := make([]int, length)
+		for i := range counts {
+			for _, v := range values {
+				if bitAt(v, i) {
+					counts[i]++
+				}
+			}
+		}
+
+		for k := -1; k <= n+1; k++ {
+			z, err := AtLeast(k, arrays...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := make([]bool, length)
+			for i, count := range counts {
+				expected[i] = count >= k
+			}
+
+			checkBools(t, "at least", z, expected)
+		}
+
+		z, err := Majority(arrays...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := make([]bool, length)
+		for i, count := range counts {
+			expected[i] = 2*count > n
+		}
+
+		checkBools(t, "majority", z, expected)
+	}
+
+	if _, err := AtLeast(1); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func benchmarkOperands(b *testing.B, n int) []*BitArray {
+	arrays := make([]*BitArray, n)
+	for i := range arrays {
+		bitArray, err := NewBitArray(1 << 20)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for j := range bitArray.blocks {
+			bitArray.blocks[j] = rand.Uint64()
+		}
+
+		arrays[i] = bitArray
+	}
+
+	return arrays
+}
+
+func BenchmarkAndAll(b *testing.B) {
+	arrays := benchmarkOperands(b, 32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := AndAll(arrays...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAndChained(b *testing.B) {
+	arrays := benchmarkOperands(b, 32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		z := arrays[0]
+		for _, a := range arrays[1:] {
+			var err error
+			if z, err = And(z, a); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkMajority(b *testing.B) {
+	arrays := benchmarkOperands(b, 32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Majority(arrays...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
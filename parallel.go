@@ -0,0 +1,132 @@
+package bitarray
+
+import (
+	"runtime"
+	"sync"
+)
+
+const (
+	// parallelThreshold is the number of blocks below which the parallel
+	// operations use the sequential path, as starting goroutines costs more
+	// than processing the blocks.
+	parallelThreshold = 1 << 14
+	// cacheLineBlocks is the number of blocks in a cache line. The chunks
+	// processed by the goroutines are multiples of it, so that no two
+	// goroutines write to the same cache line.
+	cacheLineBlocks = 8
+)
+
+// workerCount returns the number of goroutines to process n blocks on.
+// If workers is zero or negative, runtime.GOMAXPROCS(0) is used.
+func workerCount(workers, n int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if n < parallelThreshold {
+		return 1
+	}
+
+	if chunks := n / cacheLineBlocks; workers > chunks {
+		return chunks
+	}
+
+	return workers
+}
+
+// parallel splits n blocks into at most workerCount(workers, n) chunks,
+// calls f for each of them on its own goroutine and waits for them.
+func parallel(n, workers int, f func(chunk, start, end int)) {
+	workers = workerCount(workers, n)
+	if workers == 1 {
+		f(0, 0, n)
+		return
+	}
+
+	size := (n + workers - 1) / workers
+	size = (size + cacheLineBlocks - 1) &^ (cacheLineBlocks - 1)
+
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+
+		wg.Add(1)
+		go func(chunk, start, end int) {
+			defer wg.Done()
+			f(chunk, start, end)
+		}(start/size, start, end)
+	}
+
+	wg.Wait()
+}
+
+// span returns the blocks from start to end, truncated to the blocks present.
+func span(blocks []uint64, start, end int) []uint64 {
+	if end > len(blocks) {
+		end = len(blocks)
+	}
+
+	if start > end {
+		start = end
+	}
+
+	return blocks[start:end]
+}
+
+// parallelCombine is the result of op on x and y, padded to the longer one, computed on workers goroutines.
+func parallelCombine(op combineOp, x, y *BitArray, workers int) (*BitArray, error) {
+	bitArray, err := newResult(x, y, PadToLonger)
+	if err != nil {
+		return nil, err
+	}
+
+	xs, ys := x.used(), y.used()
+	parallel(len(bitArray.blocks), workers, func(_, start, end int) {
+		combine(op, bitArray.blocks[start:end], span(xs, start, end), span(ys, start, end))
+	})
+
+	bitArray.clearTail()
+	return bitArray, nil
+}
+
+// ParallelAnd is And computed on workers goroutines.
+// If workers is zero or negative, runtime.GOMAXPROCS(0) is used.
+// Small BitArrays are processed sequentially.
+func ParallelAnd(x, y *BitArray, workers int) (*BitArray, error) {
+	return parallelCombine(opAnd, x, y, workers)
+}
+
+// ParallelOr is Or computed on workers goroutines.
+// If workers is zero or negative, runtime.GOMAXPROCS(0) is used.
+// Small BitArrays are processed sequentially.
+func ParallelOr(x, y *BitArray, workers int) (*BitArray, error) {
+	return parallelCombine(opOr, x, y, workers)
+}
+
+// ParallelXor is Xor computed on workers goroutines.
+// If workers is zero or negative, runtime.GOMAXPROCS(0) is used.
+// Small BitArrays are processed sequentially.
+func ParallelXor(x, y *BitArray, workers int) (*BitArray, error) {
+	return parallelCombine(opXor, x, y, workers)
+}
+
+// ParallelOnesCount is OnesCount computed on workers goroutines.
+// If workers is zero or negative, runtime.GOMAXPROCS(0) is used.
+// Small BitArrays are processed sequentially.
+func (b *BitArray) ParallelOnesCount(workers int) int {
+	blocks := b.used()
+	counts := make([]int, workerCount(workers, len(blocks)))
+	parallel(len(blocks), workers, func(chunk, start, end int) {
+		counts[chunk] = popcount(blocks[start:end])
+	})
+
+	count := 0
+	for _, c := range counts {
+		count += c
+	}
+
+	return count
+}
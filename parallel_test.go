@@ -0,0 +1,114 @@
+package bitarray
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParallelOr(t *testing.T) {
+	funcs := map[string][2]func(x, y *BitArray, workers int) (*BitArray, error){
+		"and": {ParallelAnd, func(x, y *BitArray, _ int) (*BitArray, error) { return And(x, y) }},
+		"or":  {ParallelOr, func(x, y *BitArray, _ int) (*BitArray, error) { return Or(x, y) }},
+		"xor": {ParallelXor, func(x, y *BitArray, _ int) (*BitArray, error) { return Xor(x, y) }},
+	}
+
+	lengths := [][2]int{
+		{100, 200},
+		{parallelThreshold * bitPerBlock, parallelThreshold * bitPerBlock},
+		{parallelThreshold*bitPerBlock*3 + 5, parallelThreshold * bitPerBlock},
+		{parallelThreshold*bitPerBlock + 100, parallelThreshold*bitPerBlock*2 - 1},
+	}
+
+	for _, l := range lengths {
+		x := newRandomBitArray(t, l[0])
+		y := newRandomBitArray(t, l[1])
+		for _, workers := range []int{-1, 0, 1, 3, 8, 1 << 30} {
+			for name, f := range funcs {
+				z, err := f[0](x, y, workers)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expected, err := f[1](x, y, workers)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !z.Equal(expected) || len(z.blocks) != blockCount(z.length) {
+					t.Errorf("%v: value does not match %v %v", name, l, workers)
+				}
+			}
+
+			if x.ParallelOnesCount(workers) != x.OnesCount() {
+				t.Errorf("ones count does not match %v %v %v", l, workers, x.ParallelOnesCount(workers))
+			}
+		}
+	}
+}
+
+func TestParallel(t *testing.T) {
+	for _, n := range []int{0, parallelThreshold - 1, parallelThreshold, parallelThreshold*5 + 3} {
+		for _, workers := range []int{0, 1, 2, 7, 1 << 30} {
+			covered := make([]int, n)
+			chunks := make([]bool, workerCount(workers, n))
+			parallel(n, workers, func(chunk, start, end int) {
+				chunks[chunk] = true
+				if start%cacheLineBlocks != 0 {
+					t.Errorf("chunk is not aligned %v %v %v", n, workers, start)
+				}
+
+				for i := start; i < end; i++ {
+					covered[i]++
+				}
+			})
+
+			for i, c := range covered {
+				if c != 1 {
+					t.Fatalf("block was processed %v times %v %v %v", c, n, workers, i)
+				}
+			}
+		}
+	}
+}
+
+func benchmarkParallelOperands(b *testing.B) (*BitArray, *BitArray) {
+	x, err := NewBitArray(1 << 27)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	y, err := NewBitArray(1 << 27)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	copy(x.blocks, randomWords(len(x.blocks)))
+	copy(y.blocks, randomWords(len(y.blocks)))
+	return x, y
+}
+
+func BenchmarkParallelOr(b *testing.B) {
+	x, y := benchmarkParallelOperands(b)
+	for _, workers := range []int{1, 2, 4, 0} {
+		b.Run(fmt.Sprintf("workers=%v", workers), func(b *testing.B) {
+			b.SetBytes(int64(8 * len(x.blocks)))
+			for i := 0; i < b.N; i++ {
+				if _, err := ParallelOr(x, y, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBitArray_ParallelOnesCount(b *testing.B) {
+	x, _ := benchmarkParallelOperands(b)
+	for _, workers := range []int{1, 2, 4, 0} {
+		b.Run(fmt.Sprintf("workers=%v", workers), func(b *testing.B) {
+			b.SetBytes(int64(8 * len(x.blocks)))
+			for i := 0; i < b.N; i++ {
+				x.ParallelOnesCount(workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package bitarray
+
+import (
+	"errors"
+)
+
+// LengthPolicy selects the length of the result of a logical operation on
+// two BitArrays of different lengths.
+type LengthPolicy int
+
+const (
+	// PadToLonger gives the result the length of the longer operand and
+	// treats the missing bits of the shorter one as zero.
+	PadToLonger LengthPolicy = iota
+	// TruncateToShorter gives the result the length of the shorter operand
+	// and ignores the bits of the longer one beyond it.
+	TruncateToShorter
+	// ErrorOnMismatch fails if the lengths differ.
+	ErrorOnMismatch
+)
+
+// resultLength returns the length of the result of a logical operation on x and y.
+func resultLength(x, y *BitArray, policy LengthPolicy) (int, error) {
+	switch policy {
+	case PadToLonger:
+		return maxLength(x.length, y.length), nil
+	case TruncateToShorter:
+		if x.length < y.length {
+			return x.length, nil
+		}
+
+		return y.length, nil
+	case ErrorOnMismatch:
+		if x.length != y.length {
+			return 0, errors.New("length mismatch")
+		}
+
+		return x.length, nil
+	}
+
+	return 0, errors.New("unknown length policy")
+}
+
+// newResult constructs the result of a logical operation on x and y.
+func newResult(x, y *BitArray, policy LengthPolicy) (*BitArray, error) {
+	length, err := resultLength(x, y, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBitArray(length)
+}
+
+// AndWithPolicy is the logical AND of two BitArrays with the specified length policy.
+func AndWithPolicy(x, y *BitArray, policy LengthPolicy) (*BitArray, error) {
+	bitArray, err := newResult(x, y, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	combine(opAnd, bitArray.blocks, x.used(), y.used())
+
+	bitArray.clearTail()
+	return bitArray, nil
+}
+
+// OrWithPolicy is the logical OR of two BitArrays with the specified length policy.
+func OrWithPolicy(x, y *BitArray, policy LengthPolicy) (*BitArray, error) {
+	bitArray, err := newResult(x, y, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	combine(opOr, bitArray.blocks, x.used(), y.used())
+
+	bitArray.clearTail()
+	return bitArray, nil
+}
+
+// XorWithPolicy is the exclusive OR of two BitArrays with the specified length policy.
+func XorWithPolicy(x, y *BitArray, policy LengthPolicy) (*BitArray, error) {
+	bitArray, err := newResult(x, y, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	combine(opXor, bitArray.blocks, x.used(), y.used())
+
+	bitArray.clearTail()
+	return bitArray, nil
+}
@@ -0,0 +1,128 @@
+package bitarray
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// operands is a pair of random BitArrays of random lengths for property-based tests.
+type operands struct {
+	x, y []bool
+}
+
+func randomBools(r *rand.Rand) []bool {
+	values := make([]bool, r.Intn(300))
+	for i := range values {
+		values[i] = r.Intn(2) == 0
+	}
+
+	return values
+}
+
+// Generate implements the quick.Generator interface.
+func (operands) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(operands{x: randomBools(r), y: randomBools(r)})
+}
+
+func TestAndWithPolicy(t *testing.T) {
+	ops := map[string]func(x, y bool) bool{
+		"and": func(x, y bool) bool { return x && y },
+		"or":  func(x, y bool) bool { return x || y },
+		"xor": func(x, y bool) bool { return x != y },
+	}
+
+	funcs := map[string]func(x, y *BitArray, policy LengthPolicy) (*BitArray, error){
+		"and": AndWithPolicy,
+		"or":  OrWithPolicy,
+		"xor": XorWithPolicy,
+	}
+
+	defaults := map[string]func(x, y *BitArray) (*BitArray, error){
+		"and": And,
+		"or":  Or,
+		"xor": Xor,
+	}
+
+	for name, op := range ops {
+		name, op := name, op
+		property := func(o operands) bool {
+			x, err := FromBools(o.x)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			y, err := FromBools(o.y)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lengths := map[LengthPolicy]int{
+				PadToLonger:       maxLength(len(o.x), len(o.y)),
+				TruncateToShorter: len(o.x) + len(o.y) - maxLength(len(o.x), len(o.y)),
+				ErrorOnMismatch:   len(o.x),
+			}
+
+			for policy, length := range lengths {
+				z, err := funcs[name](x, y, policy)
+				if policy == ErrorOnMismatch && len(o.x) != len(o.y) {
+					if err == nil {
+						return false
+					}
+
+					continue
+				}
+
+				if err != nil {
+					return false
+				}
+
+				expected := make([]bool, length)
+				for i := range expected {
+					expected[i] = op(bitAt(o.x, i), bitAt(o.y, i))
+				}
+
+				checkBools(t, name, z, expected)
+			}
+
+			z, err := defaults[name](x, y)
+			if err != nil {
+				return false
+			}
+
+			padded, err := funcs[name](x, y, PadToLonger)
+			if err != nil {
+				return false
+			}
+
+			return reflect.DeepEqual(z.Bools(), padded.Bools())
+		}
+
+		if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+			t.Errorf("%v: %v", name, err)
+		}
+	}
+
+	x := newPatternBitArray(t, 10, 1)
+	if _, err := AndWithPolicy(x, x, LengthPolicy(-1)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestOrMismatchedLengths(t *testing.T) {
+	for xLength := 0; xLength < 200; xLength += 3 {
+		for yLength := 0; yLength < 200; yLength += 5 {
+			x := newPatternBitArray(t, xLength, 3)
+			y := newPatternBitArray(t, yLength, 1)
+			or, err := Or(x, y)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if or.OnesCount() < yLength {
+				t.Errorf("bits of the longer operand were dropped %v %v", xLength, yLength)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package bitarray
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// The range operations apply to the bits from start up to but not including end.
+
+// checkRange validates a range of bits.
+func (b *BitArray) checkRange(start, end int) error {
+	if start < 0 || end > b.length || start > end {
+		return errors.New("index out of range")
+	}
+
+	return nil
+}
+
+// rangeMask returns the mask of the bits of block i that are within the range.
+func rangeMask(i, start, end int) uint64 {
+	mask := uint64(max)
+	if i == start/bitPerBlock {
+		mask &= mask << uint64(start%bitPerBlock)
+	}
+
+	if i == (end-1)/bitPerBlock {
+		mask &= uint64(max) >> uint64(bitPerBlock-1-(end-1)%bitPerBlock)
+	}
+
+	return mask
+}
+
+// SetRange sets the bits in the range to true.
+func (b *BitArray) SetRange(start, end int) error {
+	if err := b.checkRange(start, end); err != nil {
+		return err
+	}
+
+	if start == end {
+		return nil
+	}
+
+	for i := start / bitPerBlock; i <= (end-1)/bitPerBlock; i++ {
+		b.blocks[i] |= rangeMask(i, start, end)
+	}
+
+	return nil
+}
+
+// ClearRange sets the bits in the range to false.
+func (b *BitArray) ClearRange(start, end int) error {
+	if err := b.checkRange(start, end); err != nil {
+		return err
+	}
+
+	if start == end {
+		return nil
+	}
+
+	for i := start / bitPerBlock; i <= (end-1)/bitPerBlock; i++ {
+		b.blocks[i] &^= rangeMask(i, start, end)
+	}
+
+	return nil
+}
+
+// FlipRange inverts the bits in the range.
+func (b *BitArray) FlipRange(start, end int) error {
+	if err := b.checkRange(start, end); err != nil {
+		return err
+	}
+
+	if start == end {
+		return nil
+	}
+
+	for i := start / bitPerBlock; i <= (end-1)/bitPerBlock; i++ {
+		b.blocks[i] ^= rangeMask(i, start, end)
+	}
+
+	return nil
+}
+
+// OnesCountRange returns the number of one bits in the range.
+func (b *BitArray) OnesCountRange(start, end int) (int, error) {
+	if err := b.checkRange(start, end); err != nil {
+		return 0, err
+	}
+
+	if start == end {
+		return 0, nil
+	}
+
+	count := 0
+	for i := start / bitPerBlock; i <= (end-1)/bitPerBlock; i++ {
+		count += bits.OnesCount64(b.blocks[i] & rangeMask(i, start, end))
+	}
+
+	return count, nil
+}
+
+// AnyInRange reports whether any bit in the range is set.
+// It returns false for an empty range.
+func (b *BitArray) AnyInRange(start, end int) (bool, error) {
+	if err := b.checkRange(start, end); err != nil {
+		return false, err
+	}
+
+	if start == end {
+		return false, nil
+	}
+
+	for i := start / bitPerBlock; i <= (end-1)/bitPerBlock; i++ {
+		if b.blocks[i]&rangeMask(i, start, end) != 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// AllInRange reports whether all bits in the range are set.
+// It returns true for an empty range.
+func (b *BitArray) AllInRange(start, end int) (bool, error) {
+	if err := b.checkRange(start, end); err != nil {
+		return false, err
+	}
+
+	if start == end {
+		return true, nil
+	}
+
+	for i := start / bitPerBlock; i <= (end-1)/bitPerBlock; i++ {
+		mask := rangeMask(i, start, end)
+		if b.blocks[i]&mask != mask {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,81 @@
+package bitarray
+
+import (
+	"testing"
+)
+
+func TestBitArray_SetRange(t *testing.T) {
+	for _, length := range []int{0, 1, 63, 64, 65, 130, 200} {
+		for start := 0; start <= length; start++ {
+			for end := start; end <= length; end++ {
+				bitArray := newPatternBitArray(t, length, 3)
+				values := bitArray.Bools()
+
+				set := make([]bool, length)
+				cleared := make([]bool, length)
+				flip := make([]bool, length)
+				count, any, all := 0, false, true
+				for i := range values {
+					in := i >= start && i < end
+					set[i] = values[i] || in
+					cleared[i] = values[i] && !in
+					flip[i] = values[i] != in
+					if in && values[i] {
+						count++
+						any = true
+					}
+
+					if in && !values[i] {
+						all = false
+					}
+				}
+
+				c, err := bitArray.OnesCountRange(start, end)
+				if err != nil || c != count {
+					t.Errorf("count does not match %v %v %v %v %v", length, start, end, c, count)
+				}
+
+				a, err := bitArray.AnyInRange(start, end)
+				if err != nil || a != any {
+					t.Errorf("any does not match %v %v %v %v", length, start, end, a)
+				}
+
+				a, err = bitArray.AllInRange(start, end)
+				if err != nil || a != all {
+					t.Errorf("all does not match %v %v %v %v", length, start, end, a)
+				}
+
+				if err := bitArray.FlipRange(start, end); err != nil {
+					t.Fatal(err)
+				}
+
+				checkBools(t, "flip", bitArray, flip)
+
+				bitArray = newPatternBitArray(t, length, 3)
+				if err := bitArray.SetRange(start, end); err != nil {
+					t.Fatal(err)
+				}
+
+				checkBools(t, "set", bitArray, set)
+
+				bitArray = newPatternBitArray(t, length, 3)
+				if err := bitArray.ClearRange(start, end); err != nil {
+					t.Fatal(err)
+				}
+
+				checkBools(t, "clear", bitArray, cleared)
+			}
+		}
+
+		bitArray := newPatternBitArray(t, length, 3)
+		for _, r := range [][2]int{{-1, 0}, {0, length + 1}, {1, 0}} {
+			if err := bitArray.SetRange(r[0], r[1]); err == nil {
+				t.Errorf("expected error %v %v", length, r)
+			}
+
+			if _, err := bitArray.OnesCountRange(r[0], r[1]); err == nil {
+				t.Errorf("expected error %v %v", length, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package bitarray
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+const (
+	superBlockBits = 4096
+	subBlockBits   = 512
+	superBlockSize = superBlockBits / bitPerBlock
+	subBlockSize   = subBlockBits / bitPerBlock
+)
+
+// RankSelect is an index over a BitArray for rank and select queries.
+//
+// It stores the number of one bits before each superblock of 4096 bits as a
+// uint64 and before each block of 512 bits, relative to its superblock, as a
+// uint16. That is 64/4096 + 16/512, about 4.7% of the size of the BitArray.
+// Rank takes constant time and select a binary search over the superblocks.
+//
+// The RankSelect shares the storage of the BitArray, which must not be
+// modified while the RankSelect is used.
+type RankSelect struct {
+	blocks []uint64
+	length int
+	ones   int
+	supers []uint64
+	subs   []uint16
+}
+
+// NewRankSelect is RankSelect constructed.
+func NewRankSelect(b *BitArray) *RankSelect {
+	blocks := b.used()
+	r := &RankSelect{
+		blocks: blocks,
+		length: b.length,
+		supers: make([]uint64, (len(blocks)+superBlockSize-1)/superBlockSize),
+		subs:   make([]uint16, (len(blocks)+subBlockSize-1)/subBlockSize),
+	}
+
+	count := 0
+	for i, u := range blocks {
+		if i%superBlockSize == 0 {
+			r.supers[i/superBlockSize] = uint64(count)
+		}
+
+		if i%subBlockSize == 0 {
+			r.subs[i/subBlockSize] = uint16(count - int(r.supers[i/superBlockSize]))
+		}
+
+		count += bits.OnesCount64(u)
+	}
+
+	r.ones = count
+	return r
+}
+
+// Length returns number of bits in the indexed BitArray.
+func (r *RankSelect) Length() int {
+	return r.length
+}
+
+// OnesCount returns the number of one bits in the indexed BitArray.
+func (r *RankSelect) OnesCount() int {
+	return r.ones
+}
+
+// Rank1 returns the number of one bits before the specified index.
+// The index may be equal to the length.
+func (r *RankSelect) Rank1(index int) (int, error) {
+	if index < 0 || index > r.length {
+		return 0, errors.New("index out of range")
+	}
+
+	if index == r.length {
+		return r.ones, nil
+	}
+
+	i := index / bitPerBlock
+	count := int(r.supers[index/superBlockBits]) + int(r.subs[index/subBlockBits])
+	for j := index / subBlockBits * subBlockSize; j < i; j++ {
+		count += bits.OnesCount64(r.blocks[j])
+	}
+
+	mask := uint64(1)<<uint64(index%bitPerBlock) - 1
+	return count + bits.OnesCount64(r.blocks[i]&mask), nil
+}
+
+// Rank0 returns the number of zero bits before the specified index.
+// The index may be equal to the length.
+func (r *RankSelect) Rank0(index int) (int, error) {
+	count, err := r.Rank1(index)
+	if err != nil {
+		return 0, err
+	}
+
+	return index - count, nil
+}
+
+// Select1 returns the index of the k-th one bit, counting from zero.
+func (r *RankSelect) Select1(k int) (int, error) {
+	if k < 0 || k >= r.ones {
+		return 0, errors.New("rank out of range")
+	}
+
+	return r.find(k, false)
+}
+
+// Select0 returns the index of the k-th zero bit, counting from zero.
+func (r *RankSelect) Select0(k int) (int, error) {
+	if k < 0 || k >= r.length-r.ones {
+		return 0, errors.New("rank out of range")
+	}
+
+	return r.find(k, true)
+}
+
+// count returns the number of one bits, or of zero bits if zeros is true,
+// among n bits with the specified number of one bits.
+func count(ones, n int, zeros bool) int {
+	if zeros {
+		return n - ones
+	}
+
+	return ones
+}
+
+// find returns the index of the k-th one bit, or zero bit if zeros is true.
+func (r *RankSelect) find(k int, zeros bool) (int, error) {
+	s := sort.Search(len(r.supers), func(s int) bool {
+		return count(int(r.supers[s]), s*superBlockBits, zeros) > k
+	}) - 1
+	k -= count(int(r.supers[s]), s*superBlockBits, zeros)
+
+	first := s * superBlockSize / subBlockSize
+	last := first + superBlockBits/subBlockBits
+	if last > len(r.subs) {
+		last = len(r.subs)
+	}
+
+	j := first
+	for j+1 < last && count(int(r.subs[j+1]), (j+1-first)*subBlockBits, zeros) <= k {
+		j++
+	}
+
+	k -= count(int(r.subs[j]), (j-first)*subBlockBits, zeros)
+	for i := j * subBlockSize; i < len(r.blocks); i++ {
+		u := r.blocks[i]
+		if zeros {
+			u = ^u
+		}
+
+		n := bits.OnesCount64(u)
+		if k < n {
+			for ; k > 0; k-- {
+				u &= u - 1
+			}
+
+			return i*bitPerBlock + bits.TrailingZeros64(u), nil
+		}
+
+		k -= n
+	}
+
+	return 0, errors.New("rank out of range")
+}
@@ -0,0 +1,82 @@
+package bitarray
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRankSelect(t *testing.T) {
+	rand.Seed(1)
+	lengths := []int{0, 1, 63, 64, 65, 511, 512, 513, 4095, 4096, 4097, 20000}
+	for _, length := range lengths {
+		for _, density := range []int{1, 2, 50, 1000} {
+			bitArray, err := NewBitArray(length)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < length; i++ {
+				if rand.Intn(density) == 0 {
+					if err := bitArray.Set(i); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			r := NewRankSelect(bitArray)
+			if r.OnesCount() != bitArray.OnesCount() || r.Length() != length {
+				t.Errorf("count does not match %v %v", length, r.OnesCount())
+			}
+
+			ones, zeros := 0, 0
+			for i := 0; i <= length; i++ {
+				rank1, err := r.Rank1(i)
+				if err != nil || rank1 != ones {
+					t.Fatalf("rank1 does not match %v %v %v %v", length, i, rank1, ones)
+				}
+
+				rank0, err := r.Rank0(i)
+				if err != nil || rank0 != zeros {
+					t.Fatalf("rank0 does not match %v %v %v %v", length, i, rank0, zeros)
+				}
+
+				if i == length {
+					break
+				}
+
+				v, _ := bitArray.Get(i)
+				if v {
+					index, err := r.Select1(ones)
+					if err != nil || index != i {
+						t.Fatalf("select1 does not match %v %v %v %v", length, ones, index, i)
+					}
+
+					ones++
+				} else {
+					index, err := r.Select0(zeros)
+					if err != nil || index != i {
+						t.Fatalf("select0 does not match %v %v %v %v", length, zeros, index, i)
+					}
+
+					zeros++
+				}
+			}
+
+			if _, err := r.Select1(ones); err == nil {
+				t.Error("expected error")
+			}
+
+			if _, err := r.Select0(zeros); err == nil {
+				t.Error("expected error")
+			}
+
+			if _, err := r.Rank1(length + 1); err == nil {
+				t.Error("expected error")
+			}
+
+			if _, err := r.Select1(-1); err == nil {
+				t.Error("expected error")
+			}
+		}
+	}
+}
@@ -0,0 +1,718 @@
+package bitarray
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+// Roaring is a compressed bitmap for sparse sets of up to 2^32 bits.
+//
+// The indices are split into chunks of 2^16 bits. Each non-empty chunk is
+// stored in the smallest fitting container: a sorted array of the set bits
+// when there are at most 4096 of them, a 2^16 bit dense bitmap otherwise,
+// or, after RunOptimize, a list of runs of consecutive set bits.
+type Roaring struct {
+	keys       []uint16
+	containers []container
+	length     int
+}
+
+// maxRoaringLength is the maximum length of a Roaring.
+const maxRoaringLength = 1 << 32
+
+// arrayMaxSize is the maximum number of values in an array container.
+const arrayMaxSize = 4096
+
+// containerWords is the number of blocks of a bitmap container.
+const containerWords = 1 << 16 / bitPerBlock
+
+// NewRoaring is Roaring constructed.
+func NewRoaring(length int) (*Roaring, error) {
+	if length < 0 {
+		return nil, errors.New("negative length argument")
+	}
+
+	if int64(length) > maxRoaringLength {
+		return nil, errors.New("length exceeds 2^32")
+	}
+
+	return &Roaring{length: length}, nil
+}
+
+// Length returns number of bits in the Roaring.
+func (r *Roaring) Length() int {
+	return r.length
+}
+
+// find returns the position of the container for key and whether it exists.
+func (r *Roaring) find(key uint16) (int, bool) {
+	i := sort.Search(len(r.keys), func(i int) bool { return r.keys[i] >= key })
+	return i, i < len(r.keys) && r.keys[i] == key
+}
+
+// Set sets the specified bit to true.
+func (r *Roaring) Set(index int) error {
+	if index < 0 || index >= r.length {
+		return errors.New("index out of range")
+	}
+
+	key, low := uint16(index>>16), uint16(index)
+	i, ok := r.find(key)
+	if ok {
+		r.containers[i] = r.containers[i].add(low)
+		return nil
+	}
+
+	r.keys = append(r.keys, 0)
+	copy(r.keys[i+1:], r.keys[i:])
+	r.keys[i] = key
+	r.containers = append(r.containers, nil)
+	copy(r.containers[i+1:], r.containers[i:])
+	r.containers[i] = &arrayContainer{values: []uint16{low}}
+	return nil
+}
+
+// Get gets the specified bit.
+func (r *Roaring) Get(index int) (bool, error) {
+	if index < 0 || index >= r.length {
+		return false, errors.New("index out of range")
+	}
+
+	i, ok := r.find(uint16(index >> 16))
+	return ok && r.containers[i].contains(uint16(index)), nil
+}
+
+// Clear sets the specified bit to false.
+func (r *Roaring) Clear(index int) error {
+	if index < 0 || index >= r.length {
+		return errors.New("index out of range")
+	}
+
+	i, ok := r.find(uint16(index >> 16))
+	if !ok {
+		return nil
+	}
+
+	c := r.containers[i].remove(uint16(index))
+	if c != nil {
+		r.containers[i] = c
+		return nil
+	}
+
+	r.keys = append(r.keys[:i], r.keys[i+1:]...)
+	r.containers = append(r.containers[:i], r.containers[i+1:]...)
+	return nil
+}
+
+// OnesCount returns the number of one bits in the Roaring.
+func (r *Roaring) OnesCount() int {
+	count := 0
+	for _, c := range r.containers {
+		count += c.cardinality()
+	}
+
+	return count
+}
+
+// SetBits returns an iterator over the indices of set bits in ascending order.
+func (r *Roaring) SetBits() func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i, c := range r.containers {
+			if !c.iterate(int(r.keys[i])<<16, yield) {
+				return
+			}
+		}
+	}
+}
+
+// Clone the Roaring.
+func (r *Roaring) Clone() *Roaring {
+	clone := &Roaring{
+		keys:       append([]uint16(nil), r.keys...),
+		containers: make([]container, len(r.containers)),
+		length:     r.length,
+	}
+
+	for i, c := range r.containers {
+		clone.containers[i] = c.clone()
+	}
+
+	return clone
+}
+
+// RunOptimize converts each container to a run container where that is smaller,
+// and run containers back to array or bitmap containers where those are smaller.
+func (r *Roaring) RunOptimize() {
+	for i, c := range r.containers {
+		runs := c.runs()
+		card := c.cardinality()
+		size := 2 * card
+		if card > arrayMaxSize {
+			size = 8 * containerWords
+		}
+
+		if 4*runs < size {
+			if _, ok := c.(*runContainer); !ok {
+				r.containers[i] = newRunContainer(c)
+			}
+		} else if _, ok := c.(*runContainer); ok {
+			var words [containerWords]uint64
+			c.fill(words[:])
+			r.containers[i] = newContainer(&words)
+		}
+	}
+}
+
+// Roaring converts the BitArray to a Roaring.
+func (b *BitArray) Roaring() (*Roaring, error) {
+	r, err := NewRoaring(b.length)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := b.used()
+	for start := 0; start < len(blocks); start += containerWords {
+		var words [containerWords]uint64
+		copy(words[:], blocks[start:])
+		if c := newContainer(&words); c != nil {
+			r.keys = append(r.keys, uint16(start/containerWords))
+			r.containers = append(r.containers, c)
+		}
+	}
+
+	return r, nil
+}
+
+// BitArray converts the Roaring to a BitArray.
+func (r *Roaring) BitArray() (*BitArray, error) {
+	bitArray, err := NewBitArray(r.length)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, c := range r.containers {
+		c.fill(bitArray.blocks[int(r.keys[i])*containerWords:])
+	}
+
+	return bitArray, nil
+}
+
+// AndRoaring is the logical AND of two Roarings.
+func AndRoaring(x, y *Roaring) *Roaring {
+	z := &Roaring{length: maxLength(x.length, y.length)}
+	for i, j := 0, 0; i < len(x.keys) && j < len(y.keys); {
+		switch {
+		case x.keys[i] < y.keys[j]:
+			i++
+		case x.keys[i] > y.keys[j]:
+			j++
+		default:
+			z.append(x.keys[i], andContainers(x.containers[i], y.containers[j]))
+			i++
+			j++
+		}
+	}
+
+	return z
+}
+
+// OrRoaring is the logical OR of two Roarings.
+func OrRoaring(x, y *Roaring) *Roaring {
+	return mergeRoaring(x, y, orContainers)
+}
+
+// XorRoaring is the exclusive OR of two Roarings.
+func XorRoaring(x, y *Roaring) *Roaring {
+	return mergeRoaring(x, y, xorContainers)
+}
+
+// mergeRoaring combines the containers of x and y with op, copying the
+// containers whose key is only in one of them.
+func mergeRoaring(x, y *Roaring, op func(x, y container) container) *Roaring {
+	z := &Roaring{length: maxLength(x.length, y.length)}
+	i, j := 0, 0
+	for i < len(x.keys) || j < len(y.keys) {
+		switch {
+		case j == len(y.keys) || i < len(x.keys) && x.keys[i] < y.keys[j]:
+			z.append(x.keys[i], x.containers[i].clone())
+			i++
+		case i == len(x.keys) || x.keys[i] > y.keys[j]:
+			z.append(y.keys[j], y.containers[j].clone())
+			j++
+		default:
+			z.append(x.keys[i], op(x.containers[i], y.containers[j]))
+			i++
+			j++
+		}
+	}
+
+	return z
+}
+
+// append appends a container unless it is nil.
+func (r *Roaring) append(key uint16, c container) {
+	if c != nil {
+		r.keys = append(r.keys, key)
+		r.containers = append(r.containers, c)
+	}
+}
+
+func maxLength(x, y int) int {
+	if x > y {
+		return x
+	}
+
+	return y
+}
+
+// container holds the low 16 bits of the set bits of a chunk.
+// The mutating methods return the container to use afterwards,
+// which is nil when the container became empty.
+type container interface {
+	contains(low uint16) bool
+	add(low uint16) container
+	remove(low uint16) container
+	cardinality() int
+	// runs returns the number of runs of consecutive set bits.
+	runs() int
+	// iterate calls yield with base plus each value in ascending order
+	// and reports whether the iteration was not stopped.
+	iterate(base int, yield func(int) bool) bool
+	// fill sets the bits of the container in words.
+	fill(words []uint64)
+	clone() container
+}
+
+// newContainer returns the array or bitmap container for the bits of words,
+// or nil if there are none.
+func newContainer(words *[containerWords]uint64) container {
+	card := 0
+	for _, u := range words {
+		card += bits.OnesCount64(u)
+	}
+
+	if card == 0 {
+		return nil
+	}
+
+	if card > arrayMaxSize {
+		return &bitmapContainer{words: *words, card: card}
+	}
+
+	values := make([]uint16, 0, card)
+	for i, u := range words {
+		for ; u != 0; u &= u - 1 {
+			values = append(values, uint16(i*bitPerBlock+bits.TrailingZeros64(u)))
+		}
+	}
+
+	return &arrayContainer{values: values}
+}
+
+// arrayContainer is a sorted array of values.
+type arrayContainer struct {
+	values []uint16
+}
+
+func (a *arrayContainer) search(low uint16) (int, bool) {
+	i := sort.Search(len(a.values), func(i int) bool { return a.values[i] >= low })
+	return i, i < len(a.values) && a.values[i] == low
+}
+
+func (a *arrayContainer) contains(low uint16) bool {
+	_, ok := a.search(low)
+	return ok
+}
+
+func (a *arrayContainer) add(low uint16) container {
+	i, ok := a.search(low)
+	if ok {
+		return a
+	}
+
+	if len(a.values) == arrayMaxSize {
+		bitmap := &bitmapContainer{}
+		a.fill(bitmap.words[:])
+		bitmap.card = len(a.values)
+		return bitmap.add(low)
+	}
+
+	a.values = append(a.values, 0)
+	copy(a.values[i+1:], a.values[i:])
+	a.values[i] = low
+	return a
+}
+
+func (a *arrayContainer) remove(low uint16) container {
+	i, ok := a.search(low)
+	if !ok {
+		return a
+	}
+
+	a.values = append(a.values[:i], a.values[i+1:]...)
+	if len(a.values) == 0 {
+		return nil
+	}
+
+	return a
+}
+
+func (a *arrayContainer) cardinality() int {
+	return len(a.values)
+}
+
+func (a *arrayContainer) runs() int {
+	runs := 0
+	for i, v := range a.values {
+		if i == 0 || a.values[i-1]+1 != v {
+			runs++
+		}
+	}
+
+	return runs
+}
+
+func (a *arrayContainer) iterate(base int, yield func(int) bool) bool {
+	for _, v := range a.values {
+		if !yield(base + int(v)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *arrayContainer) fill(words []uint64) {
+	for _, v := range a.values {
+		words[v/bitPerBlock] |= 1 << (v % bitPerBlock)
+	}
+}
+
+func (a *arrayContainer) clone() container {
+	return &arrayContainer{values: append([]uint16(nil), a.values...)}
+}
+
+// bitmapContainer is a dense bitmap of more than arrayMaxSize values.
+type bitmapContainer struct {
+	words [containerWords]uint64
+	card  int
+}
+
+func (m *bitmapContainer) contains(low uint16) bool {
+	return m.words[low/bitPerBlock]&(1<<(low%bitPerBlock)) != 0
+}
+
+func (m *bitmapContainer) add(low uint16) container {
+	mask := uint64(1) << (low % bitPerBlock)
+	if m.words[low/bitPerBlock]&mask == 0 {
+		m.words[low/bitPerBlock] |= mask
+		m.card++
+	}
+
+	return m
+}
+
+func (m *bitmapContainer) remove(low uint16) container {
+	mask := uint64(1) << (low % bitPerBlock)
+	if m.words[low/bitPerBlock]&mask == 0 {
+		return m
+	}
+
+	m.words[low/bitPerBlock] &^= mask
+	m.card--
+	if m.card <= arrayMaxSize {
+		return newContainer(&m.words)
+	}
+
+	return m
+}
+
+func (m *bitmapContainer) cardinality() int {
+	return m.card
+}
+
+func (m *bitmapContainer) runs() int {
+	runs := 0
+	carry := uint64(0)
+	for _, u := range m.words {
+		runs += bits.OnesCount64(u &^ (u<<1 | carry))
+		carry = u >> (bitPerBlock - 1)
+	}
+
+	return runs
+}
+
+func (m *bitmapContainer) iterate(base int, yield func(int) bool) bool {
+	for i, u := range m.words {
+		for ; u != 0; u &= u - 1 {
+			if !yield(base + i*bitPerBlock + bits.TrailingZeros64(u)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (m *bitmapContainer) fill(words []uint64) {
+	for i, u := range m.words {
+		if u != 0 {
+			words[i] |= u
+		}
+	}
+}
+
+func (m *bitmapContainer) clone() container {
+	clone := *m
+	return &clone
+}
+
+// interval is a run of consecutive values from start to last inclusive.
+type interval struct {
+	start, last uint16
+}
+
+// runContainer is a sorted list of non-adjacent runs.
+type runContainer struct {
+	intervals []interval
+}
+
+func newRunContainer(c container) *runContainer {
+	r := &runContainer{intervals: make([]interval, 0, c.runs())}
+	c.iterate(0, func(v int) bool {
+		n := len(r.intervals)
+		if n > 0 && int(r.intervals[n-1].last)+1 == v {
+			r.intervals[n-1].last++
+		} else {
+			r.intervals = append(r.intervals, interval{start: uint16(v), last: uint16(v)})
+		}
+
+		return true
+	})
+
+	return r
+}
+
+// search returns the position of the first run starting after low.
+func (r *runContainer) search(low uint16) int {
+	return sort.Search(len(r.intervals), func(i int) bool { return r.intervals[i].start > low })
+}
+
+func (r *runContainer) contains(low uint16) bool {
+	i := r.search(low)
+	return i > 0 && low <= r.intervals[i-1].last
+}
+
+func (r *runContainer) add(low uint16) container {
+	i := r.search(low)
+	if i > 0 && low <= r.intervals[i-1].last {
+		return r
+	}
+
+	prev := i > 0 && int(r.intervals[i-1].last)+1 == int(low)
+	next := i < len(r.intervals) && int(low)+1 == int(r.intervals[i].start)
+	switch {
+	case prev && next:
+		r.intervals[i-1].last = r.intervals[i].last
+		r.intervals = append(r.intervals[:i], r.intervals[i+1:]...)
+	case prev:
+		r.intervals[i-1].last = low
+	case next:
+		r.intervals[i].start = low
+	default:
+		r.intervals = append(r.intervals, interval{})
+		copy(r.intervals[i+1:], r.intervals[i:])
+		r.intervals[i] = interval{start: low, last: low}
+	}
+
+	return r
+}
+
+func (r *runContainer) remove(low uint16) container {
+	i := r.search(low) - 1
+	if i < 0 || low > r.intervals[i].last {
+		return r
+	}
+
+	run := r.intervals[i]
+	switch {
+	case run.start == run.last:
+		r.intervals = append(r.intervals[:i], r.intervals[i+1:]...)
+		if len(r.intervals) == 0 {
+			return nil
+		}
+	case low == run.start:
+		r.intervals[i].start++
+	case low == run.last:
+		r.intervals[i].last--
+	default:
+		r.intervals = append(r.intervals, interval{})
+		copy(r.intervals[i+1:], r.intervals[i:])
+		r.intervals[i].last = low - 1
+		r.intervals[i+1].start = low + 1
+	}
+
+	return r
+}
+
+func (r *runContainer) cardinality() int {
+	card := 0
+	for _, run := range r.intervals {
+		card += int(run.last-run.start) + 1
+	}
+
+	return card
+}
+
+func (r *runContainer) runs() int {
+	return len(r.intervals)
+}
+
+func (r *runContainer) iterate(base int, yield func(int) bool) bool {
+	for _, run := range r.intervals {
+		for v := int(run.start); v <= int(run.last); v++ {
+			if !yield(base + v) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (r *runContainer) fill(words []uint64) {
+	for _, run := range r.intervals {
+		start, end := int(run.start), int(run.last)+1
+		for i := start / bitPerBlock; i <= (end-1)/bitPerBlock; i++ {
+			words[i] |= rangeMask(i, start, end)
+		}
+	}
+}
+
+func (r *runContainer) clone() container {
+	return &runContainer{intervals: append([]interval(nil), r.intervals...)}
+}
+
+// andContainers returns the intersection of two containers, or nil if it is empty.
+func andContainers(x, y container) container {
+	xa, xIsArray := x.(*arrayContainer)
+	ya, yIsArray := y.(*arrayContainer)
+	if !xIsArray && yIsArray {
+		xa, ya, xIsArray, yIsArray = ya, xa, yIsArray, xIsArray
+		x, y = y, x
+	}
+
+	if xIsArray {
+		var values []uint16
+		if yIsArray {
+			values = make([]uint16, 0, len(xa.values))
+			for i, j := 0, 0; i < len(xa.values) && j < len(ya.values); {
+				switch {
+				case xa.values[i] < ya.values[j]:
+					i++
+				case xa.values[i] > ya.values[j]:
+					j++
+				default:
+					values = append(values, xa.values[i])
+					i++
+					j++
+				}
+			}
+		} else {
+			for _, v := range xa.values {
+				if y.contains(v) {
+					values = append(values, v)
+				}
+			}
+		}
+
+		if len(values) == 0 {
+			return nil
+		}
+
+		return &arrayContainer{values: values}
+	}
+
+	var xw, yw [containerWords]uint64
+	x.fill(xw[:])
+	y.fill(yw[:])
+	for i := range xw {
+		xw[i] &= yw[i]
+	}
+
+	return newContainer(&xw)
+}
+
+// orContainers returns the union of two containers.
+func orContainers(x, y container) container {
+	xa, xIsArray := x.(*arrayContainer)
+	ya, yIsArray := y.(*arrayContainer)
+	if xIsArray && yIsArray && len(xa.values)+len(ya.values) <= arrayMaxSize {
+		values := make([]uint16, 0, len(xa.values)+len(ya.values))
+		i, j := 0, 0
+		for i < len(xa.values) && j < len(ya.values) {
+			switch {
+			case xa.values[i] < ya.values[j]:
+				values = append(values, xa.values[i])
+				i++
+			case xa.values[i] > ya.values[j]:
+				values = append(values, ya.values[j])
+				j++
+			default:
+				values = append(values, xa.values[i])
+				i++
+				j++
+			}
+		}
+
+		values = append(values, xa.values[i:]...)
+		values = append(values, ya.values[j:]...)
+		return &arrayContainer{values: values}
+	}
+
+	var words [containerWords]uint64
+	x.fill(words[:])
+	y.fill(words[:])
+	return newContainer(&words)
+}
+
+// xorContainers returns the symmetric difference of two containers, or nil if it is empty.
+func xorContainers(x, y container) container {
+	xa, xIsArray := x.(*arrayContainer)
+	ya, yIsArray := y.(*arrayContainer)
+	if xIsArray && yIsArray && len(xa.values)+len(ya.values) <= arrayMaxSize {
+		values := make([]uint16, 0, len(xa.values)+len(ya.values))
+		i, j := 0, 0
+		for i < len(xa.values) && j < len(ya.values) {
+			switch {
+			case xa.values[i] < ya.values[j]:
+				values = append(values, xa.values[i])
+				i++
+			case xa.values[i] > ya.values[j]:
+				values = append(values, ya.values[j])
+				j++
+			default:
+				i++
+				j++
+			}
+		}
+
+		values = append(values, xa.values[i:]...)
+		values = append(values, ya.values[j:]...)
+		if len(values) == 0 {
+			return nil
+		}
+
+		return &arrayContainer{values: values}
+	}
+
+	var xw, yw [containerWords]uint64
+	x.fill(xw[:])
+	y.fill(yw[:])
+	for i := range xw {
+		xw[i] ^= yw[i]
+	}
+
+	return newContainer(&xw)
+}
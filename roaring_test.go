@@ -0,0 +1,231 @@
+package bitarray
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func checkRoaring(t *testing.T, name string, r *Roaring, expected *BitArray) {
+	t.Helper()
+	if r.Length() != expected.length {
+		t.Fatalf("%v: length does not match %v %v", name, r.Length(), expected.length)
+	}
+
+	if r.OnesCount() != expected.OnesCount() {
+		t.Errorf("%v: count does not match %v %v", name, r.OnesCount(), expected.OnesCount())
+	}
+
+	bitArray, err := r.BitArray()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range expected.blocks {
+		if bitArray.blocks[i] != expected.blocks[i] {
+			t.Fatalf("%v: value does not match %v", name, i)
+		}
+	}
+
+	prev := -1
+	r.SetBits()(func(index int) bool {
+		if index <= prev {
+			t.Errorf("%v: indices are not ascending %v %v", name, prev, index)
+		}
+
+		if v, _ := expected.Get(index); !v {
+			t.Errorf("%v: unexpected index %v", name, index)
+		}
+
+		prev = index
+		return true
+	})
+
+	for i := 0; i < 1000; i++ {
+		index := rand.Intn(expected.length)
+		x, err := r.Get(index)
+		if err != nil {
+			t.Error(err)
+		}
+
+		y, _ := expected.Get(index)
+		if x != y {
+			t.Errorf("%v: value does not match %v", name, index)
+		}
+	}
+}
+
+// newRandomBitmaps returns a Roaring and an equal BitArray with sparse,
+// dense, run and empty chunks, built by random Set and Clear calls.
+func newRandomBitmaps(t *testing.T, seed int64) (*Roaring, *BitArray) {
+	rand.Seed(seed)
+	length := 5<<16 + 100
+	r, err := NewRoaring(length)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewBitArray(length)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apply := func(index int, set bool) {
+		if set {
+			if err := r.Set(index); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := b.Set(index); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			if err := r.Clear(index); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := b.Clear(index); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for i := 0; i < 3000; i++ {
+		apply(rand.Intn(1<<16), rand.Intn(3) > 0)
+	}
+
+	for i := 0; i < 20000; i++ {
+		apply(1<<16+rand.Intn(1<<16), rand.Intn(4) > 0)
+	}
+
+	for start := 2 << 16; start < 3<<16; start += 1000 + rand.Intn(1000) {
+		for i := start; i < start+rand.Intn(500); i++ {
+			apply(i, true)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		apply(length-1-rand.Intn(100), true)
+	}
+
+	return r, b
+}
+
+func TestRoaring(t *testing.T) {
+	r, b := newRandomBitmaps(t, 1)
+	checkRoaring(t, "random", r, b)
+
+	converted, err := b.Roaring()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkRoaring(t, "converted", converted, b)
+
+	r.RunOptimize()
+	checkRoaring(t, "run optimized", r, b)
+
+	if _, ok := r.containers[2].(*runContainer); !ok {
+		t.Errorf("runs were not converted %T", r.containers[2])
+	}
+
+	for i := 0; i < 30000; i++ {
+		index := 2<<16 + rand.Intn(1<<16)
+		if rand.Intn(2) == 0 {
+			if err := r.Set(index); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := b.Set(index); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			if err := r.Clear(index); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := b.Clear(index); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	checkRoaring(t, "run modified", r, b)
+
+	r.RunOptimize()
+	checkRoaring(t, "run reoptimized", r, b)
+
+	for i := 0; i < b.length; i++ {
+		if err := r.Clear(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if r.OnesCount() != 0 || len(r.containers) != 0 {
+		t.Errorf("containers were not removed %v", len(r.containers))
+	}
+
+	if err := r.Set(b.length); err == nil {
+		t.Error("expected error")
+	}
+
+	if _, err := NewRoaring(-1); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestAndRoaring(t *testing.T) {
+	for _, optimize := range []bool{false, true} {
+		x, xb := newRandomBitmaps(t, 2)
+		y, yb := newRandomBitmaps(t, 3)
+		if optimize {
+			x.RunOptimize()
+		}
+
+		and, err := And(xb, yb)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		or, err := Or(xb, yb)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		xor, err := Xor(xb, yb)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkRoaring(t, "and", AndRoaring(x, y), and)
+		checkRoaring(t, "or", OrRoaring(x, y), or)
+		checkRoaring(t, "xor", XorRoaring(x, y), xor)
+		checkRoaring(t, "xor self", XorRoaring(x, x), &BitArray{blocks: make([]uint64, len(xb.blocks)), length: xb.length})
+
+		for _, pair := range [][2]Bitmap{{x, yb}, {xb, y}, {x, y}} {
+			z, err := AndBitmap(pair[0], pair[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkRoaring(t, "and bitmap", z.(*Roaring), and)
+		}
+
+		z, err := OrBitmap(xb, yb)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := z.(*BitArray); !ok {
+			t.Errorf("unexpected type %T", z)
+		}
+
+		z, err = XorBitmap(x, yb)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkRoaring(t, "xor bitmap", z.(*Roaring), xor)
+
+		checkRoaring(t, "clone", x.Clone(), xb)
+	}
+}
@@ -0,0 +1,99 @@
+package bitarray
+
+import (
+	"errors"
+)
+
+// RotateLeft rotates the BitArray n places to the left, towards the higher
+// indices, moving the bits shifted beyond the length back to the start.
+// A negative n rotates to the right.
+func (b *BitArray) RotateLeft(n int) (*BitArray, error) {
+	bitArray, err := NewBitArray(b.length)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.length == 0 {
+		return bitArray, nil
+	}
+
+	k := n % b.length
+	if k < 0 {
+		k += b.length
+	}
+
+	// A block of the result takes the bits from k places lower, and those
+	// before index 0 from the end of the BitArray.
+	for i := range bitArray.blocks {
+		start := i*bitPerBlock - k
+		bitArray.blocks[i] = b.blockAt(start) | b.blockAt(start+b.length)
+	}
+
+	bitArray.clearTail()
+	return bitArray, nil
+}
+
+// RotateRight rotates the BitArray n places to the right, towards the lower
+// indices, moving the bits shifted before index 0 to the end.
+// A negative n rotates to the left.
+func (b *BitArray) RotateRight(n int) (*BitArray, error) {
+	if b.length == 0 {
+		return b.RotateLeft(0)
+	}
+
+	return b.RotateLeft(-(n % b.length))
+}
+
+// ShiftMode selects how Shift treats the length and the vacated bits.
+type ShiftMode int
+
+const (
+	// ShiftLogical keeps the length, discards the bits shifted beyond it and fills the vacated bits with zero.
+	ShiftLogical ShiftMode = iota
+	// ShiftGrowing keeps all bits: a left shift by n inserts n zero bits at
+	// the start and lengthens by n, a right shift by n removes the n lowest
+	// bits and shortens by n.
+	ShiftGrowing
+	// ShiftArithmetic keeps the length like ShiftLogical, but fills the bits vacated by a right shift with the sign bit.
+	ShiftArithmetic
+)
+
+// Shift shifts the BitArray n places to the left, towards the higher indices,
+// in the specified mode. A negative n shifts -n places to the right in the
+// same mode.
+func (b *BitArray) Shift(n int, mode ShiftMode) (*BitArray, error) {
+	switch mode {
+	case ShiftLogical:
+		bitArray, err := b.Clone()
+		if err != nil {
+			return nil, err
+		}
+
+		bitArray.ShiftLeftInPlace(n)
+		return bitArray, nil
+	case ShiftGrowing:
+		if n > maxInt-b.length {
+			return nil, errors.New("length overflows int")
+		}
+
+		if n >= 0 {
+			return b.LeftShift(n)
+		}
+
+		if n <= -b.length {
+			return b.Slice(0, 0)
+		}
+
+		return b.Slice(-n, b.length)
+	case ShiftArithmetic:
+		// A right shift by the length or more fills all bits with the sign
+		// bit, and clamping keeps the negation from overflowing.
+		if n < -b.length {
+			n = -b.length
+		}
+
+		return b.ArithmeticRightShift(-n)
+	}
+
+	return nil, errors.New("unknown shift mode")
+}
@@ -0,0 +1,137 @@
+package bitarray
+
+import (
+	"testing"
+)
+
+func TestBitArray_RotateLeft(t *testing.T) {
+	for length := 0; length < 200; length++ {
+		bitArray := newPatternBitArray(t, length, 7)
+		if length > 0 {
+			if err := bitArray.Set(length - 1); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		values := bitArray.Bools()
+		for _, n := range []int{-3*length - 1, -length, -65, -64, -1, 0, 1, 5, 63, 64, 65, 130, length, 2*length + 3} {
+			expected := make([]bool, length)
+			for i := range expected {
+				j := (i - n) % length
+				if j < 0 {
+					j += length
+				}
+
+				expected[i] = values[j]
+			}
+
+			left, err := bitArray.RotateLeft(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkBools(t, "rotate left", left, expected)
+
+			right, err := bitArray.RotateRight(-n)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkBools(t, "rotate right", right, expected)
+		}
+	}
+}
+
+func TestBitArray_Shift(t *testing.T) {
+	for length := 0; length < 150; length++ {
+		for _, negative := range []bool{false, true} {
+			bitArray := newPatternBitArray(t, length, 7)
+			if negative {
+				bitArray.Flip()
+			}
+
+			values := bitArray.Bools()
+			for n := -length - 3; n < length+3; n++ {
+				logical := make([]bool, length)
+				arithmetic := make([]bool, length)
+				for i := range logical {
+					logical[i] = bitAt(values, i-n)
+					arithmetic[i] = logical[i]
+					if n < 0 && i-n >= length {
+						arithmetic[i] = bitArray.SignBit()
+					}
+				}
+
+				var growing []bool
+				if n >= 0 {
+					growing = append(make([]bool, n), values...)
+				} else if -n < length {
+					growing = values[-n:]
+				} else {
+					growing = []bool{}
+				}
+
+				expected := map[ShiftMode][]bool{
+					ShiftLogical:    logical,
+					ShiftGrowing:    growing,
+					ShiftArithmetic: arithmetic,
+				}
+
+				for mode, values := range expected {
+					shifted, err := bitArray.Shift(n, mode)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					checkBools(t, "shift", shifted, values)
+				}
+			}
+		}
+	}
+
+	if _, err := newPatternBitArray(t, 10, 1).Shift(1, ShiftMode(-1)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestBitArray_ShiftExtreme(t *testing.T) {
+	for _, length := range []int{0, 1, 64, 100} {
+		for _, negative := range []bool{false, true} {
+			bitArray := newPatternBitArray(t, length, 3)
+			if negative {
+				bitArray.Flip()
+			}
+
+			for _, n := range []int{-maxInt - 1, -maxInt, maxInt} {
+				arithmetic := make([]bool, length)
+				for i := range arithmetic {
+					arithmetic[i] = n < 0 && bitArray.SignBit()
+				}
+
+				expected := map[ShiftMode][]bool{
+					ShiftLogical:    make([]bool, length),
+					ShiftArithmetic: arithmetic,
+				}
+
+				if n < 0 {
+					expected[ShiftGrowing] = []bool{}
+				}
+
+				for mode, values := range expected {
+					shifted, err := bitArray.Shift(n, mode)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					checkBools(t, "shift", shifted, values)
+				}
+			}
+
+			if length > 0 {
+				if _, err := bitArray.Shift(maxInt, ShiftGrowing); err == nil {
+					t.Error("expected error")
+				}
+			}
+		}
+	}
+}
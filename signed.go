@@ -0,0 +1,162 @@
+package bitarray
+
+import (
+	"errors"
+	"math/big"
+)
+
+// The signed operations treat a BitArray as a two's complement integer of
+// its length, with the bit at length-1 as the sign bit. The shorter operand
+// is sign-extended to the length of the longer one.
+
+// SignBit reports whether the BitArray is negative as a two's complement integer.
+func (b *BitArray) SignBit() bool {
+	if b.length == 0 {
+		return false
+	}
+
+	i := b.length - 1
+	return b.blocks[i/bitPerBlock]>>uint64(i%bitPerBlock)&1 != 0
+}
+
+// SignExtend returns the BitArray extended to length by copying the sign bit.
+func (b *BitArray) SignExtend(length int) (*BitArray, error) {
+	if length < b.length {
+		return nil, errors.New("length shorter than BitArray")
+	}
+
+	bitArray, err := b.Slice(0, length)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.SignBit() {
+		if err := bitArray.SetRange(b.length, length); err != nil {
+			return nil, err
+		}
+	}
+
+	return bitArray, nil
+}
+
+// Neg returns the two's complement negation of the BitArray.
+// The most negative value is its own negation.
+func (b *BitArray) Neg() (*BitArray, error) {
+	zero, err := NewBitArray(b.length)
+	if err != nil {
+		return nil, err
+	}
+
+	neg, _, err := Sub(zero, b, false)
+	return neg, err
+}
+
+// ArithmeticRightShift shifts the BitArray n places to the right, filling the
+// vacated bits with the sign bit. The length is kept. A negative n shifts to
+// the left, filling with zero.
+func (b *BitArray) ArithmeticRightShift(n int) (*BitArray, error) {
+	bitArray, err := b.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 0 {
+		bitArray.ShiftLeftInPlace(-n)
+		return bitArray, nil
+	}
+
+	bitArray.ShiftRightInPlace(n)
+	if b.SignBit() {
+		start := b.length - n
+		if start < 0 {
+			start = 0
+		}
+
+		if err := bitArray.SetRange(start, b.length); err != nil {
+			return nil, err
+		}
+	}
+
+	return bitArray, nil
+}
+
+// signExtendPair sign-extends x and y to the length of the longer one.
+func signExtendPair(x, y *BitArray) (*BitArray, *BitArray, error) {
+	length := maxLength(x.length, y.length)
+	x, err := x.SignExtend(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	y, err = y.SignExtend(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return x, y, nil
+}
+
+// SignedCmp compares two BitArrays as two's complement integers and returns
+// -1 if x < y, 0 if x == y and +1 if x > y.
+func SignedCmp(x, y *BitArray) int {
+	xSign, ySign := x.SignBit(), y.SignBit()
+	if xSign != ySign {
+		if xSign {
+			return -1
+		}
+
+		return 1
+	}
+
+	x, y, err := signExtendPair(x, y)
+	if err != nil {
+		return 0
+	}
+
+	return Cmp(x, y)
+}
+
+// AddSigned returns the sum of two BitArrays as two's complement integers,
+// and whether it overflowed. Unlike the carry returned by Add, the overflow
+// is set when the sum of two operands with the same sign has the other sign.
+func AddSigned(x, y *BitArray) (*BitArray, bool, error) {
+	x, y, err := signExtendPair(x, y)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sum, _, err := Add(x, y, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	overflow := x.SignBit() == y.SignBit() && sum.SignBit() != x.SignBit()
+	return sum, overflow, nil
+}
+
+// SubSigned returns the difference of two BitArrays as two's complement
+// integers, and whether it overflowed.
+func SubSigned(x, y *BitArray) (*BitArray, bool, error) {
+	x, y, err := signExtendPair(x, y)
+	if err != nil {
+		return nil, false, err
+	}
+
+	diff, _, err := Sub(x, y, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	overflow := x.SignBit() != y.SignBit() && diff.SignBit() != x.SignBit()
+	return diff, overflow, nil
+}
+
+// SignedBigInt returns the value of the BitArray as a two's complement integer.
+func (b *BitArray) SignedBigInt() *big.Int {
+	v := b.BigInt()
+	if b.SignBit() {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(b.length)))
+	}
+
+	return v
+}
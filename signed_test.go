@@ -0,0 +1,140 @@
+package bitarray
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// signedRange returns the minimum and maximum two's complement integers of length bits.
+func signedRange(length int) (*big.Int, *big.Int) {
+	half := new(big.Int).Lsh(big.NewInt(1), uint(length-1))
+	return new(big.Int).Neg(half), new(big.Int).Sub(half, big.NewInt(1))
+}
+
+func TestAddSigned(t *testing.T) {
+	rand.Seed(2)
+	lengths := []int{1, 5, 37, 64, 65, 130}
+	for _, xLength := range lengths {
+		for _, yLength := range lengths {
+			for n := 0; n < 30; n++ {
+				x := newRandomBitArray(t, xLength)
+				y := newRandomBitArray(t, yLength)
+				if rand.Intn(2) == 0 {
+					x.Flip()
+				}
+
+				length := maxLength(xLength, yLength)
+				lo, hi := signedRange(length)
+				xb, yb := x.SignedBigInt(), y.SignedBigInt()
+
+				sum, overflow, err := AddSigned(x, y)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expected := new(big.Int).Add(xb, yb)
+				if overflow != (expected.Cmp(lo) < 0 || expected.Cmp(hi) > 0) {
+					t.Errorf("overflow does not match %v %v %v", xb, yb, overflow)
+				}
+
+				wrapped, err := FromBigInt(expected, length)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if sum.length != length || Cmp(sum, wrapped) != 0 {
+					t.Errorf("sum does not match %v %v %v", xb, yb, sum.SignedBigInt())
+				}
+
+				diff, overflow, err := SubSigned(x, y)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expected = new(big.Int).Sub(xb, yb)
+				if overflow != (expected.Cmp(lo) < 0 || expected.Cmp(hi) > 0) {
+					t.Errorf("overflow does not match %v %v %v", xb, yb, overflow)
+				}
+
+				wrapped, err = FromBigInt(expected, length)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if diff.length != length || Cmp(diff, wrapped) != 0 {
+					t.Errorf("difference does not match %v %v %v", xb, yb, diff.SignedBigInt())
+				}
+
+				if SignedCmp(x, y) != xb.Cmp(yb) {
+					t.Errorf("comparison does not match %v %v", xb, yb)
+				}
+			}
+		}
+	}
+}
+
+func TestBitArray_Neg(t *testing.T) {
+	rand.Seed(3)
+	for length := 1; length < 150; length++ {
+		x := newRandomBitArray(t, length)
+		if rand.Intn(2) == 0 {
+			x.Flip()
+		}
+
+		xb := x.SignedBigInt()
+		if x.SignBit() != (xb.Sign() < 0) {
+			t.Errorf("sign does not match %v", xb)
+		}
+
+		neg, err := x.Neg()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected, err := FromBigInt(new(big.Int).Neg(xb), length)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if Cmp(neg, expected) != 0 {
+			t.Errorf("negation does not match %v %v", xb, neg.SignedBigInt())
+		}
+
+		extended, err := x.SignExtend(length + 70)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if extended.SignedBigInt().Cmp(xb) != 0 {
+			t.Errorf("extension does not match %v %v", xb, extended.SignedBigInt())
+		}
+
+		for n := -3; n < length+3; n++ {
+			shifted, err := x.ArithmeticRightShift(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want *big.Int
+			if n >= 0 {
+				want = new(big.Int).Rsh(xb, uint(n))
+			} else {
+				want = new(big.Int).Lsh(xb, uint(-n))
+			}
+
+			wrapped, err := FromBigInt(want, length)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if Cmp(shifted, wrapped) != 0 {
+				t.Errorf("shift does not match %v %v %v", xb, n, shifted.SignedBigInt())
+			}
+		}
+	}
+
+	if _, err := newRandomBitArray(t, 10).SignExtend(9); err == nil {
+		t.Error("expected error")
+	}
+}
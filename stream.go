@@ -0,0 +1,156 @@
+package bitarray
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// streamChunkBlocks is the number of blocks written or read at a time by WriteTo and ReadFrom.
+const streamChunkBlocks = 4096
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// WriteTo implements the io.WriterTo interface.
+//
+// The stream is the binary encoding written by MarshalBinary followed by
+// the CRC-32C of it as a little-endian uint32. The bits are written in
+// fixed-size chunks, so no copy of the whole BitArray is made.
+func (b *BitArray) WriteTo(w io.Writer) (int64, error) {
+	hash := crc32.New(castagnoli)
+	mw := io.MultiWriter(w, hash)
+
+	var header [binaryHeaderSize]byte
+	header[0] = binaryVersion
+	binary.LittleEndian.PutUint64(header[1:], uint64(b.length))
+	n, err := mw.Write(header[:])
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	buf := make([]byte, 8*streamChunkBlocks)
+	size := byteSize(b.length)
+	for i := 0; i < len(b.blocks); i += streamChunkBlocks {
+		chunk := b.blocks[i:]
+		if len(chunk) > streamChunkBlocks {
+			chunk = chunk[:streamChunkBlocks]
+		}
+
+		for j, v := range chunk {
+			binary.LittleEndian.PutUint64(buf[j*8:], v)
+		}
+
+		m := len(chunk) * 8
+		if m > size {
+			m = size
+		}
+
+		n, err := mw.Write(buf[:m])
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+
+		size -= m
+	}
+
+	var trailer [4]byte
+	binary.LittleEndian.PutUint32(trailer[:], hash.Sum32())
+	n, err = w.Write(trailer[:])
+	written += int64(n)
+	return written, err
+}
+
+// ReadFrom implements the io.ReaderFrom interface.
+//
+// It reads a stream written by WriteTo and stops after its checksum,
+// leaving any following data unread. A truncated stream is reported as
+// io.ErrUnexpectedEOF and a corrupted one by a checksum mismatch.
+func (b *BitArray) ReadFrom(r io.Reader) (int64, error) {
+	hash := crc32.New(castagnoli)
+	tr := io.TeeReader(r, hash)
+
+	var header [binaryHeaderSize]byte
+	n, err := io.ReadFull(tr, header[:])
+	read := int64(n)
+	if err != nil {
+		return read, unexpectedEOF(err)
+	}
+
+	if header[0] != binaryVersion {
+		return read, errors.New("unsupported binary version")
+	}
+
+	length, err := decodeLength(header[1:])
+	if err != nil {
+		return read, err
+	}
+
+	// The blocks grow with the data actually read, so a corrupted length
+	// cannot cause a huge allocation before the checksum is verified.
+	capacity := length / bitPerBlock
+	if length%bitPerBlock != 0 {
+		capacity++
+	}
+
+	if capacity > streamChunkBlocks {
+		capacity = streamChunkBlocks
+	}
+
+	blocks := make([]uint64, 0, capacity)
+	buf := make([]byte, 8*streamChunkBlocks)
+	size := byteSize(length)
+	for size > 0 {
+		m := len(buf)
+		if m > size {
+			m = size
+		}
+
+		n, err := io.ReadFull(tr, buf[:m])
+		read += int64(n)
+		if err != nil {
+			return read, unexpectedEOF(err)
+		}
+
+		for j := m; j < len(buf) && j%8 != 0; j++ {
+			buf[j] = 0
+		}
+
+		for j := 0; j < m; j += 8 {
+			blocks = append(blocks, binary.LittleEndian.Uint64(buf[j:]))
+		}
+
+		size -= m
+	}
+
+	var trailer [4]byte
+	n, err = io.ReadFull(r, trailer[:])
+	read += int64(n)
+	if err != nil {
+		return read, unexpectedEOF(err)
+	}
+
+	if binary.LittleEndian.Uint32(trailer[:]) != hash.Sum32() {
+		return read, errors.New("checksum mismatch")
+	}
+
+	mod := length % bitPerBlock
+	if mod != 0 && blocks[len(blocks)-1]>>uint64(mod) != 0 {
+		return read, errors.New("bits set beyond length")
+	}
+
+	b.blocks = blocks
+	b.length = length
+	return read, nil
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
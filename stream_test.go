@@ -0,0 +1,106 @@
+package bitarray
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestBitArray_WriteTo(t *testing.T) {
+	lengths := []int{streamChunkBlocks*bitPerBlock*2 + 5, streamChunkBlocks * bitPerBlock}
+	for length := 0; length < 300; length++ {
+		lengths = append(lengths, length)
+	}
+
+	for _, length := range lengths {
+		bitArray, err := NewBitArray(length)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < bitArray.length; i += 7 {
+			if err := bitArray.Set(i); err != nil {
+				t.Error(err)
+			}
+		}
+
+		var buf bytes.Buffer
+		written, err := bitArray.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if written != int64(buf.Len()) {
+			t.Errorf("size does not match %v %v %v", length, written, buf.Len())
+		}
+
+		data, err := bitArray.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(buf.Bytes()[:len(data)], data) {
+			t.Errorf("stream does not match binary encoding %v", length)
+		}
+
+		buf.WriteString("next")
+		readFrom := &BitArray{}
+		read, err := readFrom.ReadFrom(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if read != written {
+			t.Errorf("size does not match %v %v %v", length, read, written)
+		}
+
+		if buf.String() != "next" {
+			t.Errorf("following data was consumed %v", length)
+		}
+
+		if readFrom.length != length {
+			t.Fatalf("length does not match %v %v", length, readFrom.length)
+		}
+
+		for i := range bitArray.blocks {
+			if bitArray.blocks[i] != readFrom.blocks[i] {
+				t.Errorf("value does not match %v %v", length, i)
+			}
+		}
+	}
+}
+
+func TestBitArray_ReadFrom(t *testing.T) {
+	bitArray, err := NewBitArray(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < bitArray.length; i += 3 {
+		if err := bitArray.Set(i); err != nil {
+			t.Error(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := bitArray.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	for i := 0; i < len(data); i++ {
+		readFrom := &BitArray{}
+		if _, err := readFrom.ReadFrom(bytes.NewReader(data[:i])); err != io.ErrUnexpectedEOF {
+			t.Errorf("truncated stream was not detected %v %v", i, err)
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		corrupted := append([]byte{}, data...)
+		corrupted[i] ^= 0x10
+		readFrom := &BitArray{}
+		if _, err := readFrom.ReadFrom(bytes.NewReader(corrupted)); err == nil {
+			t.Errorf("corrupted stream was not detected %v", i)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package bitarray
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// BitView is a window of a BitArray that shares its storage.
+// Changes through the BitView are visible in the BitArray and vice versa.
+// A BitView must not be used after the length of its BitArray changes.
+type BitView struct {
+	parent *BitArray
+	offset int
+	length int
+}
+
+// View returns a BitView of the bits from start up to but not including end.
+func (b *BitArray) View(start, end int) (*BitView, error) {
+	if err := b.checkRange(start, end); err != nil {
+		return nil, err
+	}
+
+	return &BitView{
+		parent: b,
+		offset: start,
+		length: end - start,
+	}, nil
+}
+
+// View returns a BitView of the bits of the BitView from start up to but not including end.
+func (v *BitView) View(start, end int) (*BitView, error) {
+	if start < 0 || end > v.length || start > end {
+		return nil, errors.New("index out of range")
+	}
+
+	return &BitView{
+		parent: v.parent,
+		offset: v.offset + start,
+		length: end - start,
+	}, nil
+}
+
+// Length returns number of bits in the BitView.
+func (v *BitView) Length() int {
+	return v.length
+}
+
+// Offset returns the index in the BitArray of the first bit of the BitView.
+func (v *BitView) Offset() int {
+	return v.offset
+}
+
+// Set sets the specified bit to true.
+func (v *BitView) Set(index int) error {
+	if index < 0 || index >= v.length {
+		return errors.New("index out of range")
+	}
+
+	return v.parent.Set(v.offset + index)
+}
+
+// Get gets the specified bit.
+func (v *BitView) Get(index int) (bool, error) {
+	if index < 0 || index >= v.length {
+		return false, errors.New("index out of range")
+	}
+
+	return v.parent.Get(v.offset + index)
+}
+
+// Clear sets the specified bit to false.
+func (v *BitView) Clear(index int) error {
+	if index < 0 || index >= v.length {
+		return errors.New("index out of range")
+	}
+
+	return v.parent.Clear(v.offset + index)
+}
+
+// blockCount returns the number of blocks of the BitView.
+func (v *BitView) blockCount() int {
+	return blockCount(v.length)
+}
+
+// mask returns the mask of the bits of block i that are within the BitView.
+func (v *BitView) mask(i int) uint64 {
+	rest := v.length - i*bitPerBlock
+	if rest >= bitPerBlock {
+		return max
+	}
+
+	return uint64(max) >> uint64(bitPerBlock-rest)
+}
+
+// block returns the i-th block of the BitView.
+func (v *BitView) block(i int) uint64 {
+	return v.parent.blockAt(v.offset+i*bitPerBlock) & v.mask(i)
+}
+
+// setBlock sets the i-th block of the BitView.
+func (v *BitView) setBlock(i int, value uint64) {
+	v.parent.setBlockAt(v.offset+i*bitPerBlock, value, v.mask(i))
+}
+
+// setBlockAt sets the bits selected by mask of the 64 bits starting at the specified index.
+func (b *BitArray) setBlockAt(index int, value, mask uint64) {
+	i := index / bitPerBlock
+	shift := uint64(index % bitPerBlock)
+	value &= mask
+	b.blocks[i] = b.blocks[i]&^(mask<<shift) | value<<shift
+	if shift != 0 && i+1 < len(b.blocks) {
+		b.blocks[i+1] = b.blocks[i+1]&^(mask>>(bitPerBlock-shift)) | value>>(bitPerBlock-shift)
+	}
+}
+
+// OnesCount returns the number of one bits in the BitView.
+func (v *BitView) OnesCount() int {
+	count := 0
+	for i := 0; i < v.blockCount(); i++ {
+		count += bits.OnesCount64(v.block(i))
+	}
+
+	return count
+}
+
+// SetBits returns an iterator over the indices of set bits in ascending order.
+func (v *BitView) SetBits() func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i := 0; i < v.blockCount(); i++ {
+			for u := v.block(i); u != 0; u &= u - 1 {
+				if !yield(i*bitPerBlock + bits.TrailingZeros64(u)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Clone copies the bits of the BitView into a new BitArray.
+func (v *BitView) Clone() (*BitArray, error) {
+	return v.parent.Slice(v.offset, v.offset+v.length)
+}
+
+// operand returns x, or a copy of it if it overlaps the BitView,
+// so that writing to the BitView does not change x while it is read.
+func (v *BitView) operand(x *BitView) (*BitView, error) {
+	if x.length != v.length {
+		return nil, errors.New("length mismatch")
+	}
+
+	if x.parent != v.parent || x.offset+x.length <= v.offset || v.offset+v.length <= x.offset || x.offset == v.offset {
+		return x, nil
+	}
+
+	clone, err := x.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return clone.View(0, clone.length)
+}
+
+// And sets the BitView to the logical AND of itself and x, which must have the same length.
+func (v *BitView) And(x *BitView) error {
+	x, err := v.operand(x)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < v.blockCount(); i++ {
+		v.setBlock(i, v.block(i)&x.block(i))
+	}
+
+	return nil
+}
+
+// Or sets the BitView to the logical OR of itself and x, which must have the same length.
+func (v *BitView) Or(x *BitView) error {
+	x, err := v.operand(x)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < v.blockCount(); i++ {
+		v.setBlock(i, v.block(i)|x.block(i))
+	}
+
+	return nil
+}
+
+// Xor sets the BitView to the exclusive OR of itself and x, which must have the same length.
+func (v *BitView) Xor(x *BitView) error {
+	x, err := v.operand(x)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < v.blockCount(); i++ {
+		v.setBlock(i, v.block(i)^x.block(i))
+	}
+
+	return nil
+}
+
+// AndNot clears the bits of the BitView that are set in x, which must have the same length.
+func (v *BitView) AndNot(x *BitView) error {
+	x, err := v.operand(x)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < v.blockCount(); i++ {
+		v.setBlock(i, v.block(i)&^x.block(i))
+	}
+
+	return nil
+}
+
+// Flip inverts all bits of the BitView.
+func (v *BitView) Flip() {
+	for i := 0; i < v.blockCount(); i++ {
+		v.setBlock(i, ^v.block(i))
+	}
+}
+
+// CopyFrom copies the bits of x, which must have the same length, into the BitView.
+func (v *BitView) CopyFrom(x *BitView) error {
+	x, err := v.operand(x)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < v.blockCount(); i++ {
+		v.setBlock(i, x.block(i))
+	}
+
+	return nil
+}
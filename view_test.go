@@ -0,0 +1,169 @@
+package bitarray
+
+import (
+	"testing"
+)
+
+func TestBitArray_View(t *testing.T) {
+	bitArray := newPatternBitArray(t, 300, 7)
+	values := bitArray.Bools()
+	for start := 0; start <= bitArray.length; start += 5 {
+		for end := start; end <= bitArray.length; end += 13 {
+			view, err := bitArray.View(start, end)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			count := 0
+			for i := 0; i < view.Length(); i++ {
+				v, err := view.Get(i)
+				if err != nil {
+					t.Error(err)
+				}
+
+				if v != values[start+i] {
+					t.Errorf("value does not match %v %v %v", start, end, i)
+				}
+
+				if v {
+					count++
+				}
+			}
+
+			if view.OnesCount() != count {
+				t.Errorf("count does not match %v %v %v %v", start, end, view.OnesCount(), count)
+			}
+
+			clone, err := view.Clone()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkBools(t, "clone", clone, values[start:end])
+
+			index := 0
+			view.SetBits()(func(i int) bool {
+				for !values[start+index] {
+					index++
+				}
+
+				if i != index {
+					t.Errorf("index does not match %v %v %v %v", start, end, i, index)
+				}
+
+				index++
+				return true
+			})
+		}
+	}
+
+	view, err := bitArray.View(10, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := view.Set(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _ := bitArray.Get(11); !v {
+		t.Error("set through view is not visible")
+	}
+
+	if err := view.Set(10); err == nil {
+		t.Error("expected error")
+	}
+
+	if _, err := bitArray.View(10, 301); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestBitView_And(t *testing.T) {
+	ops := map[string]func(x, y bool) bool{
+		"and":    func(x, y bool) bool { return x && y },
+		"or":     func(x, y bool) bool { return x || y },
+		"xor":    func(x, y bool) bool { return x != y },
+		"andNot": func(x, y bool) bool { return x && !y },
+		"copy":   func(x, y bool) bool { return y },
+	}
+
+	methods := map[string]func(v, x *BitView) error{
+		"and":    (*BitView).And,
+		"or":     (*BitView).Or,
+		"xor":    (*BitView).Xor,
+		"andNot": (*BitView).AndNot,
+		"copy":   (*BitView).CopyFrom,
+	}
+
+	for name, op := range ops {
+		for _, length := range []int{0, 1, 63, 64, 65, 150} {
+			for _, xOffset := range []int{0, 1, 37, 64, 100} {
+				for _, yOffset := range []int{0, 3, 64, 90} {
+					for _, shared := range []bool{false, true} {
+						x := newPatternBitArray(t, 300, 3)
+						y := x
+						if !shared {
+							y = newPatternBitArray(t, 300, 5)
+						}
+
+						expected := x.Bools()
+						ys := y.Bools()
+						for i := 0; i < length; i++ {
+							expected[xOffset+i] = op(expected[xOffset+i], ys[yOffset+i])
+						}
+
+						xView, err := x.View(xOffset, xOffset+length)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						yView, err := y.View(yOffset, yOffset+length)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						if err := methods[name](xView, yView); err != nil {
+							t.Fatal(err)
+						}
+
+						checkBools(t, name, x, expected)
+					}
+				}
+			}
+		}
+	}
+
+	x := newPatternBitArray(t, 100, 3)
+	xView, err := x.View(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	yView, err := x.View(0, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := xView.And(yView); err == nil {
+		t.Error("expected error")
+	}
+
+	expected := x.Bools()
+	for i := 10; i < 80; i++ {
+		expected[i] = !expected[i]
+	}
+
+	view, err := yView.View(0, 0)
+	if err != nil || view.Length() != 0 {
+		t.Fatal(err)
+	}
+
+	view, err = x.View(10, 80)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view.Flip()
+	checkBools(t, "flip", x, expected)
+}